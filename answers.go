@@ -0,0 +1,253 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// fieldOverrides holds the values given with the field flags, environment
+// variables, the answers file, a profile or the last install, keyed by
+// field key. The first source to give a value wins. They replace the
+// defaults before prompting, so the prompts offer them as their defaults.
+var fieldOverrides = map[string]string{}
+
+// fieldFlag is the flag.Value of a configField.
+type fieldFlag struct {
+	field configField
+}
+
+func (value fieldFlag) String() string {
+	return fieldOverrides[value.field.key]
+}
+
+func (value fieldFlag) Set(text string) error {
+	if err := value.field.set(&allConfig{}, text, true); err != nil {
+		return err
+	}
+	fieldOverrides[value.field.key] = text
+	return nil
+}
+
+// IsBoolFlag lets boolean fields be given as -tls.on instead of -tls.on=true.
+func (value fieldFlag) IsBoolFlag() bool {
+	if value.field.value == nil {
+		return false
+	}
+	_, ok := value.field.value(&allConfig{}).(*bool)
+	return ok
+}
+
+// registerFieldFlags adds a flag for every field that can be set.
+func registerFieldFlags(flags *flag.FlagSet) {
+	for _, field := range configFields {
+		if field.value == nil {
+			continue
+		}
+		usage := field.description
+		if field.defaultValue != "" {
+			usage = fmt.Sprintf("%s (default %s)", usage, field.defaultValue)
+		}
+		usage = fmt.Sprintf("%s [$%s]", usage, field.envName())
+		flags.Var(fieldFlag{field}, field.flagName(), usage)
+	}
+}
+
+// unansweredFields lists the flags of the applicable fields whose value
+// fails their validator, e.g. a peer nobody gave. The prompts catch these
+// on a terminal; reading answers from stdin they are only reported.
+func (cfg *allConfig) unansweredFields() []string {
+	missing := []string{}
+	for _, field := range configFields {
+		if field.value == nil || field.validator == nil || !cfg.fieldApplies(field) {
+			continue
+		}
+		if err := field.validator(field.get(cfg)); err != nil {
+			missing = append(missing, "-"+field.flagName())
+		}
+	}
+	return missing
+}
+
+// envPrefix starts the names of the environment variables that set fields.
+const envPrefix = "DGRAPH_HELPER_"
+
+// envName is the environment variable of the field, e.g.
+// DGRAPH_HELPER_TLS_ON for tls.on and DGRAPH_HELPER_ZERO_PORT_OFFSET for
+// zero port_offset.
+func (field configField) envName() string {
+	name := strings.NewReplacer(" ", "_", ".", "_").Replace(field.key)
+	return envPrefix + strings.ToUpper(name)
+}
+
+// readEnvironment adds the DGRAPH_HELPER_* environment variables to
+// fieldOverrides. Values given as flags take precedence.
+func readEnvironment() error {
+	fieldsByEnv := map[string]configField{}
+	for _, field := range configFields {
+		if field.value != nil {
+			fieldsByEnv[field.envName()] = field
+		}
+	}
+	unknown := []string{}
+	for _, variable := range os.Environ() {
+		parts := strings.SplitN(variable, "=", 2)
+		name, text := parts[0], parts[1]
+		if !strings.HasPrefix(name, envPrefix) {
+			continue
+		}
+		field, ok := fieldsByEnv[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		if _, given := fieldOverrides[field.key]; given {
+			continue
+		}
+		if err := field.set(&allConfig{}, text, true); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		fieldOverrides[field.key] = text
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("Unknown environment variables %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// helperConfigDir holds the files dgraph_helper keeps between runs.
+const helperConfigDir = "/etc/dgraph_helper"
+
+// lastAnswersFilepath holds the answers of the last confirmed install.
+var lastAnswersFilepath = path.Join(helperConfigDir, "last.yaml")
+
+// readAnswersFile adds the answers in filename, a yaml map of flag names to
+// values, to fieldOverrides. Values given as flags or environment variables
+// take precedence.
+func readAnswersFile(filename string) error {
+	return readAnswers(filename, true)
+}
+
+// readLastAnswers adds the answers of the last install with the lowest
+// precedence. Answers that no longer apply, e.g. a certificate file this
+// host does not have, are skipped.
+func readLastAnswers() error {
+	err := readAnswers(lastAnswersFilepath, false)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// readAnswers reads filename into fieldOverrides. When strict is false
+// unknown and invalid answers are skipped instead of failing.
+func readAnswers(filename string, strict bool) error {
+	yamlBytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	answers := map[string]interface{}{}
+	if err := yaml.Unmarshal(yamlBytes, &answers); err != nil {
+		return fmt.Errorf("Could not parse %s: %v", filename, err)
+	}
+	return addAnswers(filename, answers, strict)
+}
+
+// addAnswers adds answers, keyed by flag name, to fieldOverrides unless a
+// source read before already gave them. source names them in errors.
+func addAnswers(source string, answers map[string]interface{}, strict bool) error {
+	fieldsByFlag := map[string]configField{}
+	for _, field := range configFields {
+		if field.value != nil {
+			fieldsByFlag[field.flagName()] = field
+		}
+	}
+	unknown := []string{}
+	for name, answer := range answers {
+		field, ok := fieldsByFlag[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		if _, given := fieldOverrides[field.key]; given {
+			continue
+		}
+		text := fmt.Sprint(answer)
+		if err := field.set(&allConfig{}, text, true); err != nil {
+			if !strict {
+				continue
+			}
+			return fmt.Errorf("%s: %v", source, err)
+		}
+		fieldOverrides[field.key] = text
+	}
+	if len(unknown) > 0 && strict {
+		sort.Strings(unknown)
+		return fmt.Errorf("%s: unknown answers %s", source, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// answers returns the settable fields as an answers file would hold
+// them. Secrets are left out, and so are values the next run computes
+// itself: the release's defaults, the recommended memory, the
+// subdirectories of the install dir and the peer of a local zero. Those follow a changed release,
+// host or install dir instead of pinning the old ones.
+func (cfg *allConfig) answers() map[string]interface{} {
+	computed := defaultConfig()
+	computed.DgraphVersion = cfg.DgraphVersion
+	computed.schema().setDefaults(&computed)
+	computed.setRecommendedMemoryMb()
+	computed.installDir = cfg.installDir
+	computed.setSubdirs()
+	// the release and the install dir themselves are answers
+	computed.DgraphVersion = defaultDgraphVersion
+	computed.installDir = defaultConfig().installDir
+	answers := map[string]interface{}{}
+	for _, field := range configFields {
+		if field.value == nil || field.secret {
+			continue
+		}
+		value, _ := field.yamlValue(cfg)
+		if computedValue, _ := field.yamlValue(&computed); fmt.Sprint(value) == fmt.Sprint(computedValue) {
+			continue
+		}
+		answers[field.flagName()] = value
+	}
+	if cfg.RunZero && cfg.PeerIP == "localhost" && cfg.PeerPort == zeroGrpcPort+cfg.ZeroPortOffset {
+		// changeRoles points the data server at the zero on this host
+		delete(answers, fieldByKey("peer ip").flagName())
+		delete(answers, fieldByKey("peer port").flagName())
+	}
+	return answers
+}
+
+// writeLastAnswers saves the confirmed answers for the next run.
+func (cfg *allConfig) writeLastAnswers() error {
+	yamlBytes, err := yaml.Marshal(cfg.answers())
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(helperConfigDir, 0755); err != nil {
+		return err
+	}
+	header := "# answers of the last install, offered as defaults by the next one (see -ignore-last)\n"
+	return ioutil.WriteFile(lastAnswersFilepath, append([]byte(header), yamlBytes...), 0600)
+}
+
+// applyOverrides sets the fields given as flags or answers.
+func (cfg *allConfig) applyOverrides() {
+	for _, field := range configFields {
+		if text, ok := fieldOverrides[field.key]; ok {
+			// validated when the flag or answer was read
+			field.set(cfg, text, false)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// addDirToTar adds dir and everything below it to tw under prefix, keeping
+// modes and ownership.
+func addDirToTar(tw *tar.Writer, dir string, prefix string) error {
+	return filepath.Walk(dir, func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, filename)
+		if err != nil {
+			return err
+		}
+		return addToTar(tw, filename, info, path.Join(prefix, filepath.ToSlash(rel)))
+	})
+}
+
+// addFileToTar adds a single file to tw as name.
+func addFileToTar(tw *tar.Writer, filename string, name string) error {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	return addToTar(tw, filename, info, name)
+}
+
+func addToTar(tw *tar.Writer, filename string, info os.FileInfo, name string) error {
+	link := ""
+	if info.Mode()&os.ModeSymlink != 0 {
+		var err error
+		if link, err = os.Readlink(filename); err != nil {
+			return err
+		}
+	}
+	// FileInfoHeader fills in the uid and gid from the stat of the file
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if info.IsDir() {
+		header.Name += "/"
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(tw, file)
+	return err
+}
+
+// extractTarEntry writes one entry of an archive to dest under root,
+// restoring its mode and ownership. Symlinks that point outside of root are
+// rejected, so later entries cannot be written through them.
+func extractTarEntry(tr *tar.Reader, header *tar.Header, dest string, root string) error {
+	if dest != path.Clean(dest) || !strings.HasPrefix(path.Clean(dest), "/") {
+		return fmt.Errorf("Refusing to extract to %s (expected a clean absolute path)", dest)
+	}
+	mode := os.FileMode(header.Mode).Perm()
+	switch header.Typeflag {
+	case tar.TypeDir:
+		if err := os.MkdirAll(dest, mode); err != nil {
+			return err
+		}
+	case tar.TypeReg:
+		if err := os.MkdirAll(path.Dir(dest), os.ModePerm); err != nil {
+			return err
+		}
+		file, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(file, tr)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	case tar.TypeSymlink:
+		target := path.Join(path.Dir(dest), header.Linkname)
+		if path.IsAbs(header.Linkname) || (target != root && !strings.HasPrefix(target, root+"/")) {
+			return fmt.Errorf("Refusing to extract the symlink %s to %s (outside of %s)", header.Name, header.Linkname, root)
+		}
+		if err := os.Symlink(header.Linkname, dest); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("Unsupported entry %s in archive", header.Name)
+	}
+	if err := os.Lchown(dest, header.Uid, header.Gid); err != nil {
+		return err
+	}
+	if header.Typeflag == tar.TypeSymlink {
+		return nil
+	}
+	return os.Chmod(dest, mode)
+}
+
+// entryDest maps an archive entry under prefix to a path under dir.
+// It returns "" for entries that are not under prefix or that escape dir.
+func entryDest(name string, prefix string, dir string) string {
+	name = strings.TrimSuffix(name, "/")
+	if name != prefix && !strings.HasPrefix(name, prefix+"/") {
+		return ""
+	}
+	dest := path.Join(dir, strings.TrimPrefix(name, prefix))
+	if dest != dir && !strings.HasPrefix(dest, dir+"/") {
+		return ""
+	}
+	return dest
+}
@@ -0,0 +1,355 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/tls"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"time"
+)
+
+const manifestName = "manifest.json"
+
+// backupTimeFormat is used in archive names and for moved-aside directories.
+const backupTimeFormat = "20060102-150405"
+
+// backupManifest is the first entry of every backup archive.
+type backupManifest struct {
+	Created       time.Time `json:"created"`
+	DgraphRelease string    `json:"dgraph_release"` // the flagSchema version, e.g. v1.0
+	DgraphVersion string    `json:"dgraph_version"` // the output of dgraph version
+	// Online backups hold an export instead of the data directories.
+	Online bool `json:"online"`
+	// Paths maps each top level entry of the archive to where it came from.
+	Paths  map[string]string `json:"paths"`
+	Config string            `json:"config"`
+}
+
+// Backup archives P, W and config.yaml into a timestamped tarball. The data
+// server is stopped while its directories are copied. With -online it keeps
+// running and an export is archived instead.
+func Backup(args []string) {
+	flags := flag.NewFlagSet("backup", flag.ExitOnError)
+	configPath := flags.String("config", path.Join(defaultConfig().installDir, defaultConfig().yamlFilename), "config.yaml of the dgraph to back up")
+	outDir := flags.String("out", ".", "directory to write the archive to")
+	online := flags.Bool("online", false, "archive a fresh export instead of stopping dgraph")
+	flags.Parse(args)
+
+	cfg, err := readConfigDotYaml(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	configBytes, err := ioutil.ReadFile(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	version, err := dgraphVersion()
+	if err != nil {
+		log.Fatalf("Could not get the dgraph version: %v", err)
+	}
+	manifest := backupManifest{
+		Created:       time.Now(),
+		DgraphRelease: cfg.DgraphVersion,
+		DgraphVersion: version,
+		Online:        *online,
+		Paths:         map[string]string{"config.yaml": *configPath},
+		Config:        string(configBytes),
+	}
+	if *online {
+		exported, err := cfg.export()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, name := range exported {
+			manifest.Paths[path.Join("export", name)] = path.Join(cfg.Export, name)
+		}
+	} else {
+		if err := ensurePermissions(); err != nil {
+			log.Fatal(err)
+		}
+		manifest.Paths["p"] = cfg.P
+		manifest.Paths["w"] = cfg.W
+		stopDgraphService(cfg.serviceName())
+	}
+	filename := path.Join(*outDir, fmt.Sprintf("dgraph-backup-%s.tar.gz", manifest.Created.Format(backupTimeFormat)))
+	err = writeBackup(filename, manifest)
+	if !*online {
+		cfg.restartAndCheck()
+	}
+	if err != nil {
+		os.Remove(filename)
+		log.Fatal(err)
+	}
+	fmt.Printf("Wrote %s\n", filename)
+}
+
+func writeBackup(filename string, manifest backupManifest) error {
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	header := &tar.Header{Name: manifestName, Mode: 0600, Size: int64(len(manifestBytes)), ModTime: manifest.Created}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if _, err := tw.Write(manifestBytes); err != nil {
+		return err
+	}
+	for name, source := range manifest.Paths {
+		info, err := os.Stat(source)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			err = addDirToTar(tw, source, name)
+		} else {
+			err = addFileToTar(tw, source, name)
+		}
+		if err != nil {
+			return fmt.Errorf("Could not archive %s: %v", source, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// export asks the data server for an export and returns the names of the
+// entries it added to the Export directory.
+func (cfg *allConfig) export() ([]string, error) {
+	before, err := ioutil.ReadDir(cfg.Export)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]bool{}
+	for _, info := range before {
+		existing[info.Name()] = true
+	}
+	client := &http.Client{Transport: &http.Transport{
+		// only localhost is asked, and dgraph may use a self-signed certificate
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+	resp, err := client.Get(cfg.exportURL())
+	if err != nil {
+		return nil, err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Export failed: %s answered %s", cfg.exportURL(), resp.Status)
+	}
+	after, err := ioutil.ReadDir(cfg.Export)
+	if err != nil {
+		return nil, err
+	}
+	exported := []string{}
+	for _, info := range after {
+		if !existing[info.Name()] {
+			exported = append(exported, info.Name())
+		}
+	}
+	if len(exported) == 0 {
+		return nil, fmt.Errorf("The export did not write anything to %s", cfg.Export)
+	}
+	return exported, nil
+}
+
+// Restore checks the manifest of a backup made by Backup, stops dgraph,
+// moves the current directories aside, restores the archived ones with
+// their ownership and restarts dgraph.
+func Restore(args []string) {
+	flags := flag.NewFlagSet("restore", flag.ExitOnError)
+	force := flags.Bool("force", false, "restore even if the installed dgraph version differs from the backup's")
+	configPath := flags.String("config", path.Join(defaultConfig().installDir, defaultConfig().yamlFilename), "config.yaml of the dgraph to restore into")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		log.Fatal("Usage: dgraph_helper restore [-force] [-config config.yaml] <archive>")
+	}
+	if err := ensurePermissions(); err != nil {
+		log.Fatal(err)
+	}
+	archive := flags.Arg(0)
+	manifest, err := readManifest(archive)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if manifest.Online {
+		log.Fatal("This backup holds an export, not data directories. Load it with dgraph's live or bulk loader.")
+	}
+	version, err := dgraphVersion()
+	if err != nil {
+		log.Fatalf("Could not get the installed dgraph version: %v", err)
+	}
+	if version != manifest.DgraphVersion && !*force {
+		log.Fatalf("The backup was made with %q but %q is installed. Use -force to restore anyway.", manifest.DgraphVersion, version)
+	}
+	if _, err := schemaFor(manifest.DgraphRelease); err != nil {
+		log.Fatal(err)
+	}
+	installed, err := readConfigDotYaml(*configPath)
+	if err != nil {
+		log.Fatalf("Could not read the install to restore into: %v", err)
+	}
+	if err := checkManifestPaths(manifest, installed); err != nil {
+		log.Fatal(err)
+	}
+	cfg := defaultConfig()
+	cfg.DgraphVersion = manifest.DgraphRelease
+	stopDgraphService(cfg.serviceName())
+	suffix := ".pre-restore-" + time.Now().Format(backupTimeFormat)
+	moved := []string{}
+	for _, dest := range manifest.Paths {
+		if _, err := os.Stat(dest); err == nil {
+			if err := os.Rename(dest, dest+suffix); err != nil {
+				undoRestore(nil, moved, suffix)
+				installed.restartAndCheck()
+				log.Fatal(err)
+			}
+			moved = append(moved, dest)
+			fmt.Printf("Moved %s to %s\n", dest, dest+suffix)
+		}
+	}
+	if err := extractBackup(archive, manifest); err != nil {
+		extracted := []string{}
+		for _, dest := range manifest.Paths {
+			extracted = append(extracted, dest)
+		}
+		undoRestore(extracted, moved, suffix)
+		installed.restartAndCheck()
+		log.Fatalf("Could not restore %s: %v", archive, err)
+	}
+	cfg, err = readConfigDotYaml(manifest.Paths["config.yaml"])
+	if err != nil {
+		log.Fatal(err)
+	}
+	cfg.restartAndCheck()
+	fmt.Printf("Restored %s. Remove the %s directories once dgraph looks right.\n", archive, suffix)
+}
+
+// undoRestore removes the paths a failed restore extracted to and moves the
+// originals in moved back.
+func undoRestore(extracted []string, moved []string, suffix string) {
+	for _, dest := range extracted {
+		if err := os.RemoveAll(dest); err != nil {
+			fmt.Println(err)
+		}
+	}
+	for _, dest := range moved {
+		if err := os.Rename(dest+suffix, dest); err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Printf("Moved %s back to %s\n", dest+suffix, dest)
+	}
+}
+
+func openBackup(archive string) (*tar.Reader, func(), error) {
+	file, err := os.Open(archive)
+	if err != nil {
+		return nil, nil, err
+	}
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return tar.NewReader(gz), func() { gz.Close(); file.Close() }, nil
+}
+
+func readManifest(archive string) (backupManifest, error) {
+	manifest := backupManifest{}
+	tr, closeBackup, err := openBackup(archive)
+	if err != nil {
+		return manifest, err
+	}
+	defer closeBackup()
+	header, err := tr.Next()
+	if err != nil || header.Name != manifestName {
+		return manifest, fmt.Errorf("%s is not a dgraph_helper backup (no %s)", archive, manifestName)
+	}
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return manifest, fmt.Errorf("Invalid %s in %s: %v", manifestName, archive, err)
+	}
+	if manifest.Paths["config.yaml"] == "" {
+		return manifest, fmt.Errorf("The %s in %s is missing paths", manifestName, archive)
+	}
+	if !manifest.Online && (manifest.Paths["p"] == "" || manifest.Paths["w"] == "") {
+		return manifest, fmt.Errorf("The %s in %s is missing paths", manifestName, archive)
+	}
+	return manifest, nil
+}
+
+// checkManifestPaths returns an error unless the archive only restores the
+// config.yaml, P and W of the installed dgraph. Restore moves aside and
+// replaces whatever the manifest names, so it must not name anything else.
+func checkManifestPaths(manifest backupManifest, installed allConfig) error {
+	want := map[string]string{
+		"config.yaml": installed.configDotYamlFilepath(),
+		"p":           installed.P,
+		"w":           installed.W,
+	}
+	for entry, dest := range manifest.Paths {
+		if path.Clean(dest) != path.Clean(want[entry]) {
+			return fmt.Errorf("The backup restores %s to %s but this install keeps it in %q. Relocate the install to match first.", entry, dest, want[entry])
+		}
+	}
+	return nil
+}
+
+func extractBackup(archive string, manifest backupManifest) error {
+	tr, closeBackup, err := openBackup(archive)
+	if err != nil {
+		return err
+	}
+	defer closeBackup()
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Name == manifestName {
+			continue
+		}
+		dest, root := "", ""
+		for prefix, dir := range manifest.Paths {
+			if dest = entryDest(header.Name, prefix, dir); dest != "" {
+				root = dir
+				break
+			}
+		}
+		if dest == "" {
+			return fmt.Errorf("Unexpected entry %s in %s", header.Name, archive)
+		}
+		if err := extractTarEntry(tr, header, dest, root); err != nil {
+			return err
+		}
+	}
+}
+
+// restartAndCheck starts the data server again and waits for it to be healthy.
+func (cfg *allConfig) restartAndCheck() {
+	startDgraphService(cfg.serviceName())
+	if err := waitForHealth(cfg.healthURL(), healthTimeout); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s is healthy\n", cfg.serviceName())
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	caCertFilename   = "ca.crt"
+	caKeyFilename    = "ca.key"
+	nodeCertFilename = "node.crt"
+	nodeKeyFilename  = "node.key"
+)
+
+const (
+	caValidFor   = 10 * 365 * 24 * time.Hour
+	nodeValidFor = 2 * 365 * 24 * time.Hour
+)
+
+// Certs generates a local CA and a server certificate for this node under
+// installDir/tls and points the TLS fields of config.yaml at them.
+// An existing CA in that directory is reused, so copying ca.crt and ca.key
+// to the other nodes before running certs there gives the whole cluster
+// one CA.
+func Certs(args []string) {
+	flags := flag.NewFlagSet("certs", flag.ExitOnError)
+	configPath := flags.String("config", path.Join(defaultConfig().installDir, defaultConfig().yamlFilename), "config.yaml to update with the certificate paths")
+	hosts := flags.String("hosts", "", "comma separated extra hostnames or IPs for the certificate")
+	flags.Parse(args)
+
+	cfg, err := readConfigDotYaml(*configPath)
+	configExists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		log.Fatal(err)
+	}
+	extraHosts := []string{}
+	if *hosts != "" {
+		extraHosts = strings.Split(*hosts, ",")
+	}
+	if err := cfg.generateCerts(extraHosts); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("CA certificate:     %s\n", cfg.TlsCaCerts)
+	fmt.Printf("Server certificate: %s\n", cfg.TlsCert)
+	fmt.Printf("Server key:         %s\n", cfg.TlsCertKey)
+	if configExists {
+		cfg.writeConfigDotYaml()
+		fmt.Printf("Updated %s. Run `systemctl restart %s` to use the new certificate.\n", *configPath, cfg.serviceName())
+	}
+}
+
+func (cfg *allConfig) tlsDir() string {
+	return path.Join(cfg.installDir, "tls")
+}
+
+// setGeneratedCertPaths points the TLS fields at the files generateCerts writes.
+func (cfg *allConfig) setGeneratedCertPaths() {
+	cfg.TlsOn = true
+	cfg.TlsCaCerts = path.Join(cfg.tlsDir(), caCertFilename)
+	cfg.TlsCert = path.Join(cfg.tlsDir(), nodeCertFilename)
+	cfg.TlsCertKey = path.Join(cfg.tlsDir(), nodeKeyFilename)
+	cfg.TlsCertKeyPassphrase = ""
+}
+
+// generateCerts writes a CA (unless one exists) and a server certificate
+// signed by it into tlsDir, then sets the TLS fields to their paths.
+// The certificate is valid for MyIP, this host's name, localhost and
+// extraHosts.
+func (cfg *allConfig) generateCerts(extraHosts []string) error {
+	if err := os.MkdirAll(cfg.tlsDir(), 0700); err != nil {
+		return err
+	}
+	if err := os.Chmod(cfg.tlsDir(), 0700); err != nil {
+		return err
+	}
+	caCert, caKey, err := cfg.loadOrCreateCA()
+	if err != nil {
+		return err
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	hostname, _ := os.Hostname()
+	template, err := certTemplate(hostname, nodeValidFor)
+	if err != nil {
+		return err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	addSANs(template, append([]string{cfg.MyIP, hostname, "localhost", "127.0.0.1", "::1"}, extraHosts...))
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return err
+	}
+	cfg.setGeneratedCertPaths()
+	if err := writePEM(cfg.TlsCert, "CERTIFICATE", der, 0644); err != nil {
+		return err
+	}
+	return writeKey(cfg.TlsCertKey, key)
+}
+
+// loadOrCreateCA reads the CA from tlsDir, creating it if it does not exist.
+func (cfg *allConfig) loadOrCreateCA() (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certFile := path.Join(cfg.tlsDir(), caCertFilename)
+	keyFile := path.Join(cfg.tlsDir(), caKeyFilename)
+	if _, err := os.Stat(certFile); err == nil {
+		return loadCA(certFile, keyFile)
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	template, err := certTemplate("dgraph_helper CA", caValidFor)
+	if err != nil {
+		return nil, nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := writePEM(certFile, "CERTIFICATE", der, 0644); err != nil {
+		return nil, nil, err
+	}
+	if err := writeKey(keyFile, key); err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	return cert, key, err
+}
+
+func loadCA(certFile string, keyFile string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certPEM, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	certBlock, _ := pem.Decode(certPEM)
+	keyBlock, _ := pem.Decode(keyPEM)
+	if certBlock == nil || keyBlock == nil {
+		return nil, nil, fmt.Errorf("Could not decode the CA in %s", path.Dir(certFile))
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Could not parse %s (only EC keys made by dgraph_helper are supported): %v", keyFile, err)
+	}
+	return cert, key, nil
+}
+
+func certTemplate(commonName string, validFor time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"dgraph_helper"}},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(validFor),
+	}, nil
+}
+
+// addSANs adds each non-empty host to the template as an IP or DNS name.
+func addSANs(template *x509.Certificate, hosts []string) {
+	seen := map[string]bool{}
+	for _, host := range hosts {
+		host = strings.TrimSpace(host)
+		if host == "" || seen[host] {
+			continue
+		}
+		seen[host] = true
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+}
+
+func writeKey(filename string, key *ecdsa.PrivateKey) error {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	return writePEM(filename, "EC PRIVATE KEY", der, 0600)
+}
+
+func writePEM(filename string, blockType string, der []byte, perm os.FileMode) error {
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := ioutil.WriteFile(filename, pemBytes, perm); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing file
+	return os.Chmod(filename, perm)
+}
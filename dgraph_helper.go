@@ -2,15 +2,16 @@ package main
 
 import (
 	"errors"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"net"
 	"os"
 	"path"
 	"runtime"
 	"strconv"
 	"strings"
-	"time"
 
 	"golang.org/x/sys/unix"
 
@@ -24,8 +25,74 @@ import (
 
 const systemDpath = "/etc/systemd/system/"
 
+const dgraphBinary = "/usr/local/bin/dgraph"
+
+var resolveHosts = flag.Bool("resolve-hosts", false, "check that peer and my hostnames resolve while prompting")
+var outputFormat = flag.String("output", "table", "format of the configuration summary: table, json, yaml or markdown")
+var answersFile = flag.String("answers", "", "yaml file of answers (flag names to values) to use as the defaults of the prompts")
+var profileName = flag.String("profile", "", "start install from a profile: dev-single, prod-single, prod-cluster-node or one in /etc/dgraph_helper/profiles")
+var ignoreLast = flag.Bool("ignore-last", false, "do not offer the answers of the last install as defaults")
+
 func main() {
-	Install()
+	flag.Usage = usage
+	registerFieldFlags(flag.CommandLine)
+	flag.Parse()
+	if err := readEnvironment(); err != nil {
+		log.Fatal(err)
+	}
+	if *answersFile != "" {
+		if err := readAnswersFile(*answersFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if !containsString(summaryFormats, *outputFormat) {
+		log.Fatalf("Unknown --output %q (use one of %s)", *outputFormat, strings.Join(summaryFormats, ", "))
+	}
+	switch flag.Arg(0) {
+	case "", "install":
+		Install()
+	case "doctor":
+		Doctor(flag.Args()[1:])
+	case "certs":
+		Certs(flag.Args()[1:])
+	case "uninstall":
+		Uninstall(flag.Args()[1:])
+	case "backup":
+		Backup(flag.Args()[1:])
+	case "restore":
+		Restore(flag.Args()[1:])
+	case "relocate":
+		Relocate(flag.Args()[1:])
+	case "logs":
+		Logs(flag.Args()[1:])
+	case "status":
+		Status(flag.Args()[1:])
+	case "show":
+		Show(flag.Args()[1:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage: dgraph_helper [flags] [command]
+
+Commands:
+  install    prompt for a configuration and install dgraph as a service (default)
+  doctor     check this host for problems that would break or slow down an install (-config to check an installed one)
+  certs      generate a local CA and a server certificate and use them in config.yaml
+  uninstall  stop and remove the dgraph services (data is kept)
+  backup     archive the data directories and config.yaml
+  restore    restore an archive made by backup
+  relocate   move the data directories of an install and update its config
+  logs       show the logs of a dgraph service (-f to follow, --since to limit)
+  status     report the state of the dgraph services (--json for scripts)
+  show       print the configuration summary of an install (--output to pick a format)
+
+Flags:
+`)
+	flag.PrintDefaults()
 }
 
 func ensureLinux() error {
@@ -53,133 +120,120 @@ type allConfig struct {
 	MyPort         int
 	TotalGroups    int
 	SelectedGroups []int
+	GenerateCerts  bool
+	DgraphVersion  string // selects the flagSchema of the release being installed
+	RunZero        bool   // run dgraph zero on this host (releases with zero only)
+	RunAlpha       bool   // run the data server (dgraph, dgraph server or dgraph alpha) on this host
+	RunRatel       bool   // run the Ratel UI on this host (releases with ratel only)
+	RatelPort      int
+	LogDestination string // logToJournald or logToFiles
+	// scheduled export fields (see exports.go)
+	ScheduleExports bool
+	ExportSchedule  string // systemd OnCalendar expression
+	ExportRetention int    // number of exports to keep
+	// dgraph zero fields (written to zero.yaml)
+	ZeroIdx        int
+	ZeroReplicas   int
+	ZeroPortOffset int
+	ZeroW          string
+	ZeroPeerIP     string
+	ZeroPeerPort   int
 	// yaml.config fields
-	P            string  `yaml:"p"`            // (default "p") Directory to store posting lists.
-	W            string  `yaml:"w"`            // (default "w") Directory to store raft write-ahead logs.
-	Export       string  `yaml:"export"`       // (default "exports") Directory to store exports.
-	Port         int     `yaml:"port"`         // (default 8080) Port to run HTTP service on.
-	GrpcPort     int     `yaml:"grpc_port"`    // (default 9080) Port to run gRPC service on.
-	Workerport   int     `yaml:"workerport"`   // (default 12345) Port used by worker for internal communication.
-	Idx          int     `yaml:"idx"`          // (default 1) RAFT ID that this server will use to join RAFT groups.
-	Groups       string  `yaml:"groups"`       // (default "0,1") RAFT groups handled by this server.
-	Gentlecommit float64 `yaml:"gentlecommit"` // (default 0.1)
-	Trace        float64 `yaml:"trace"`
-	Debugmode    bool    `yaml:"debugmode"`
-	MemoryMb     float64 `yaml:"memory_mb"`
+	P            string  // (default "p") Directory to store posting lists.
+	W            string  // (default "w") Directory to store raft write-ahead logs.
+	Export       string  // (default "exports") Directory to store exports.
+	Port         int     // (default 8080) Port to run HTTP service on.
+	GrpcPort     int     // (default 9080) Port to run gRPC service on.
+	Workerport   int     // (default 12345) Port used by worker for internal communication.
+	Idx          int     // (default 1) RAFT ID that this server will use to join RAFT groups.
+	Groups       string  // (default "0,1") RAFT groups handled by this server.
+	Gentlecommit float64 // (default 0.1)
+	Trace        float64
+	Debugmode    bool
+	MemoryMb     float64
 	// command-line fields
 	Bindall bool
+	// systemd unit fields
+	MemoryMax int // MemoryMax= in MB (0 means no limit)
 	// Peer    string // IP_ADDRESS:PORT of any healthy peer.
 	// My      string // addr:port of this server, so other Dgraph servers can talk to this
 
-	// // Engine Tuning Fields
-	// Pending          int    // (default 1000)  Number of pending queries. Useful for rate limiting.
-	// PendingProposals int    // (default 2000) Number of pending mutation proposals. Useful for rate limiting.
-	// PortOffset       int    //(default 0) Value added to all listening port numbers.
-	// PostingTables    string // (default "loadtoram")(oneof ["loadtoram", "memorymap", "nothing"]) Specifies how Badger LSM tree is stored. Options are loadtoram, memorymap and nothing; which consume most to least RAM while providing best to worst performance respectively.
-	// Sc               uint   // Max number of pending entries in wal after which snapshot is taken (default 1000)
-	// UI               string // (default "/usr/local/share/dgraph/assets") Directory which contains assets for the user interface
-	// Cpu          bool
-	// Block        int
-	// Dumpsg       string
-	// ExpandEdge   bool // (default true)
-	// ExposeTrace  bool // (default false)
-	// GroupConf    string //
-	// Mem          string //
-	// Nomutations     bool //Don't allow mutations on this server
-
-	// // TLS Fields
-	// TlsCaCerts           string // CA Certs file path.
-	// TlsCert              string //  Certificate file path.
-	// TlsCertKey           string // Certificate key file path.
-	// TlsCertKeyPassphrase string // Certificate key passphrase.
-	// TlsClientAuth        string // Enable TLS client authentication
-	// TlsMaxVersion        string // (default "TLS12") TLS max version.
-	// TlsMinVersion        string // (default "TLS11") TLS min version.
-	// TlsOn                string //  Use TLS connections with clients.
-	// TlsUseSystemCa       bool   //  Include System CA into CA Certs.
-
+	// Advanced engine fields
+	Pending          int    // (default 1000) Number of pending queries. Useful for rate limiting.
+	PendingProposals int    // (default 2000) Number of pending mutation proposals. Useful for rate limiting.
+	PortOffset       int    // (default 0) Value added to all listening port numbers.
+	PostingTables    string // (default "loadtoram")(oneof ["loadtoram", "memorymap", "nothing"]) Specifies how Badger LSM tree is stored. Options are loadtoram, memorymap and nothing; which consume most to least RAM while providing best to worst performance respectively.
+	Sc               int    // (default 1000) Max number of pending entries in wal after which snapshot is taken
+	UI               string // (default "/usr/local/share/dgraph/assets") Directory which contains assets for the user interface
+	ExpandEdge       bool   // (default true) Enables the expand() feature.
+	ExposeTrace      bool   // (default false) Allow trace endpoint to be accessible from remote
+	Nomutations      bool   // Don't allow mutations on this server
+	GroupConf        string // Path to the group config file (empty for none)
+	// Profiling fields (empty or 0 disables them)
+	Cpu    string // Write cpu profile to file
+	Mem    string // Write memory profile to file
+	Block  int    // Block profiling rate
+	Dumpsg string // Directory to dump subgraphs
+
+	// TLS fields
+	TlsOn                bool   // Use TLS connections with clients.
+	TlsCert              string // Certificate file path.
+	TlsCertKey           string // Certificate key file path.
+	TlsCertKeyPassphrase string // Certificate key passphrase.
+	TlsCaCerts           string // CA Certs file path.
+	TlsClientAuth        string // Enable TLS client authentication
+	TlsMinVersion        string // (default "TLS11") TLS min version.
+	TlsMaxVersion        string // (default "TLS12") TLS max version.
+	TlsUseSystemCa       bool   // Include System CA into CA Certs.
 }
 
 func defaultConfig() allConfig {
-	return allConfig{
-		installDir:     "/var/lib/dgraph",
+	cfg := allConfig{
 		yamlFilename:   "config.yaml",
-		Groups:         "0,1",
-		PeerIP:         "",
-		PeerPort:       12345,
-		MyIP:           "",
-		Workerport:     12345,
-		Port:           8080,
-		GrpcPort:       9080,
-		Trace:          0.33,
-		Gentlecommit:   0.1,
-		MemoryMb:       1025.00,
-		Debugmode:      false,
 		SelectedGroups: []int{},
-		TotalGroups:    2,
-		Idx:            1,
 	}
+	cfg.setFieldDefaults()
+	return cfg
 }
 
 func (cfg *allConfig) toYAML() ([]byte, error) {
-	params := map[string]interface{}{
-		"p":            cfg.P,
-		"w":            cfg.W,
-		"export":       cfg.Export,
-		"port":         cfg.Port,
-		"grpc_port":    cfg.GrpcPort,
-		"workerport":   cfg.Workerport,
-		"idx":          cfg.Idx,
-		"groups":       cfg.Groups,
-		"gentlecommit": cfg.Gentlecommit,
-		"trace":        cfg.Trace,
-		"debugmode":    cfg.Debugmode,
-		"memory_mb":    cfg.MemoryMb,
-		"bindall":      cfg.Bindall,
-	}
-	peer := cfg.Peer()
-	if peer != "" {
-		params["peer"] = peer
-	}
-	my := cfg.My()
-	if my != "" {
-		params["my"] = my
+	params := cfg.yamlParams(false)
+	yamlBytes, err := yaml.Marshal(cfg.schema().apply(params))
+	if err != nil {
+		return nil, err
 	}
-	return yaml.Marshal(params)
+	header := fmt.Sprintf("# written by dgraph_helper for dgraph %s\n", cfg.DgraphVersion)
+	return append([]byte(header), yamlBytes...), nil
 }
 
-func (cfg *allConfig) wantsToChangeSubdirectories() bool {
-	return prompt.InputYesOrNo("Change dgraph's subdirectories?", false)
-}
-
-func (cfg *allConfig) wantsToChangeInstallDir() bool {
-	message := fmt.Sprintf("Change dgraph's base directory? [%s]", cfg.installDir)
-	return prompt.InputYesOrNo(message, false)
-}
-
-func (cfg *allConfig) wantsToChangePorts() bool {
-	return prompt.InputYesOrNo("Change dgraph's ports config?", false)
-}
-
-func (cfg *allConfig) wantsToChangeCluster() bool {
-	return prompt.InputYesOrNo("Change dgraph's cluster config?", false)
+// schema returns the flagSchema of the dgraph release being installed.
+func (cfg *allConfig) schema() *flagSchema {
+	schema, err := schemaFor(cfg.DgraphVersion)
+	if err != nil {
+		panic(err)
+	}
+	return schema
 }
 
-func (cfg *allConfig) wantsToChangeEngine() bool {
-	return prompt.InputYesOrNo("Change dgraph's engine config?", false)
+// ask runs change unless the dgraph release being installed rejects key.
+func (cfg *allConfig) ask(key string, change func()) {
+	if cfg.schema().supports(key) {
+		change()
+	}
 }
 
-func (cfg *allConfig) wantsToCommitConfig() bool {
-	return prompt.InputYesOrNo("Proceed with install?", true)
+func (cfg *allConfig) changeDgraphVersion() {
+	cfg.DgraphVersion = prompt.SelectString("Which dgraph release are you installing?", fieldHelp("dgraph version"), dgraphVersions(), cfg.DgraphVersion)
+	cfg.schema().setDefaults(cfg)
+	// the release's defaults must not replace values given as flags or answers
+	cfg.applyOverrides()
 }
 
 func (cfg *allConfig) writeSystemDUnit() {
-	filename := path.Join(systemDpath, "dgraph.service")
-	err := ioutil.WriteFile(filename, []byte(cfg.systemDUnit()), os.ModePerm)
+	err := ioutil.WriteFile(cfg.systemDUnitFilepath(), []byte(cfg.systemDUnit()), os.ModePerm)
 	if err != nil {
 		panic(err)
 	}
-	reloadDaemons()
 }
 
 func (cfg *allConfig) writeConfigDotYaml() {
@@ -188,46 +242,154 @@ func (cfg *allConfig) writeConfigDotYaml() {
 		panic(err)
 	}
 	// install config.yaml
-	err = ioutil.WriteFile(cfg.configDotYamlFilepath(), yamlBytes, os.ModePerm)
+	perm := os.ModePerm
+	if cfg.TlsCertKeyPassphrase != "" {
+		// keep the passphrase away from other users
+		perm = 0600
+	}
+	err = ioutil.WriteFile(cfg.configDotYamlFilepath(), yamlBytes, perm)
 	if err != nil {
 		panic(err)
 	}
 }
 
-func (cfg *allConfig) configDotYamlFilepath() string {
-	return path.Join(cfg.installDir, cfg.yamlFilename)
+// readConfigDotYaml reads an installed config.yaml. Fields that config.yaml
+// does not hold keep their defaults.
+func readConfigDotYaml(filename string) (allConfig, error) {
+	cfg := defaultConfig()
+	cfg.installDir = path.Dir(filename)
+	cfg.yamlFilename = path.Base(filename)
+	yamlBytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return cfg, err
+	}
+	if match := versionCommentRegex.FindSubmatch(yamlBytes); match != nil {
+		cfg.DgraphVersion = string(match[1])
+	}
+	schema, err := schemaFor(cfg.DgraphVersion)
+	if err != nil {
+		return cfg, fmt.Errorf("Could not read %s: %v", filename, err)
+	}
+	schema.setDefaults(&cfg)
+	params := map[string]interface{}{}
+	if err := yaml.Unmarshal(yamlBytes, &params); err != nil {
+		return cfg, fmt.Errorf("Could not parse %s: %v", filename, err)
+	}
+	// rename the release's keys back to the keys of configFields
+	params = schema.unapply(params)
+	if err := cfg.setYAMLParams(params); err != nil {
+		return cfg, fmt.Errorf("Could not read %s: %v", filename, err)
+	}
+	if peer, ok := params["peer"].(string); ok && peer != "" {
+		host, port, err := net.SplitHostPort(peer)
+		if err != nil {
+			return cfg, fmt.Errorf("Invalid peer %s in %s", peer, filename)
+		}
+		cfg.PeerIP = host
+		cfg.PeerPort, _ = strconv.Atoi(port)
+	}
+	if my, ok := params["my"].(string); ok && my != "" {
+		// the port of my is always the workerport
+		host, _, err := net.SplitHostPort(my)
+		if err != nil {
+			return cfg, fmt.Errorf("Invalid my %s in %s", my, filename)
+		}
+		cfg.MyIP = host
+	}
+	if dir, ok := params["tls_dir"].(string); ok && dir != "" && schema.tlsDir {
+		// the release reads fixed file names from tls_dir instead of tls.* keys
+		cfg.TlsOn = true
+		cfg.TlsCaCerts = path.Join(dir, caCertFilename)
+		cfg.TlsCert = path.Join(dir, nodeCertFilename)
+		cfg.TlsCertKey = path.Join(dir, nodeKeyFilename)
+	}
+	return cfg, nil
 }
 
-func (cfg *allConfig) isFirstServer() bool {
-	return prompt.InputYesOrNo("Is this the first server in the cluster?", false)
+// serviceName is the systemd unit of the data server: dgraph for releases
+// without zero, dgraph-server or dgraph-alpha for the others.
+func (cfg *allConfig) serviceName() string {
+	if !cfg.schema().hasZero() {
+		return "dgraph"
+	}
+	return "dgraph-" + cfg.schema().command
 }
 
-func (cfg *allConfig) changeInstallDir() {
-	cfg.installDir = prompt.InputString("The directory to store data folders and config files", cfg.installDir, prompt.AlwaysValid)
+// serviceNames are the systemd units of the roles this host runs, zero first.
+func (cfg *allConfig) serviceNames() []string {
+	names := []string{}
+	if cfg.RunZero {
+		names = append(names, zeroServiceName)
+	}
+	if cfg.RunAlpha {
+		names = append(names, cfg.serviceName())
+	}
+	if cfg.RunRatel {
+		names = append(names, ratelServiceName)
+	}
+	return names
 }
 
-func (cfg *allConfig) changeP() {
-	cfg.P = prompt.InputString("The directory to store posting lists?", cfg.P, prompt.AlwaysValid)
+func unitFilepath(service string) string {
+	return path.Join(systemDpath, service+".service")
 }
 
-func (cfg *allConfig) changeW() {
-	cfg.W = prompt.InputString("The directory to store write-ahead logs?", cfg.W, prompt.AlwaysValid)
+func (cfg *allConfig) systemDUnitFilepath() string {
+	return unitFilepath(cfg.serviceName())
+}
+
+func (cfg *allConfig) configDotYamlFilepath() string {
+	return path.Join(cfg.installDir, cfg.yamlFilename)
+}
+
+func (cfg *allConfig) isFirstServer() bool {
+	return prompt.InputYesOrNo("Is this the first server in the cluster?", fieldHelp("first server"), false)
 }
 
-func (cfg *allConfig) changeExport() {
-	cfg.Export = prompt.InputString("The directory to store exports?", cfg.Export, prompt.AlwaysValid)
+func (cfg *allConfig) changeP() {
+	oldP := cfg.P
+	cfg.P = prompt.InputString("The directory to store posting lists?", fieldHelp("p"), cfg.P, prompt.AlwaysValid)
+	warnIfAbandoningData(oldP, cfg.P)
 }
 
-func (cfg *allConfig) changePort() {
-	cfg.Port = prompt.InputInteger("The port to serve http?", cfg.Port, true, prompt.PortValidator)
+func (cfg *allConfig) changeW() {
+	oldW := cfg.W
+	cfg.W = prompt.InputString("The directory to store write-ahead logs?", fieldHelp("w"), cfg.W, prompt.AlwaysValid)
+	warnIfAbandoningData(oldW, cfg.W)
 }
 
-func (cfg *allConfig) changeGrpcPort() {
-	cfg.GrpcPort = prompt.InputInteger("The port to serve grpc?", cfg.GrpcPort, true, prompt.PortValidator)
+// warnIfAbandoningData points at the relocate command when a directory that
+// already holds data is replaced by another one.
+func warnIfAbandoningData(oldDir string, newDir string) {
+	if oldDir == newDir {
+		return
+	}
+	entries, err := ioutil.ReadDir(oldDir)
+	if err != nil || len(entries) == 0 {
+		return
+	}
+	fmt.Printf("%s already holds data that the new config will not use. To move it run `dgraph_helper relocate` instead.\n", oldDir)
 }
 
-func (cfg *allConfig) changeWorkerport() {
-	cfg.Workerport = prompt.InputInteger("The port for worker communication?", cfg.Workerport, true, prompt.PortValidator)
+// changePorts asks for the ports of the roles this host runs, or for
+// port_offset when the release has fixed ports.
+func (cfg *allConfig) changePorts() {
+	if cfg.RunZero {
+		cfg.changeZeroPortOffset()
+	}
+	if cfg.RunRatel {
+		cfg.askField("ratel port")
+	}
+	if !cfg.RunAlpha {
+		return
+	}
+	if !cfg.schema().supports("port") {
+		cfg.changePortOffset()
+		return
+	}
+	cfg.askField("port")
+	cfg.askField("grpc_port")
+	cfg.askField("workerport")
 }
 
 func (cfg *allConfig) changePeer() {
@@ -235,29 +397,30 @@ func (cfg *allConfig) changePeer() {
 	cfg.changePeerPort()
 }
 func (cfg *allConfig) changePeerIP() {
-	cfg.PeerIP = prompt.InputString("The IP of a healty peer in the cluster?", cfg.PeerIP, prompt.IPv4Validator)
+	message := fmt.Sprintf("The IP or hostname of %s?", cfg.schema().peerDescription)
+	cfg.PeerIP = prompt.InputString(message, fieldHelp("peer"), cfg.PeerIP, hostValidator())
 }
 
 func (cfg *allConfig) changePeerPort() {
-	cfg.PeerPort = prompt.InputInteger("The workerport of the same peer", cfg.PeerPort, true, prompt.PortValidator)
+	cfg.PeerPort = prompt.InputInteger(cfg.schema().peerPortMessage, fieldHelp("peer"), cfg.PeerPort, true, prompt.PortValidator)
 }
 
 func (cfg *allConfig) Peer() string {
 	if cfg.PeerIP == "" {
 		return ""
 	}
-	return fmt.Sprintf("%s:%d", cfg.PeerIP, cfg.PeerPort)
+	return net.JoinHostPort(cfg.PeerIP, strconv.Itoa(cfg.PeerPort))
 }
 
 func (cfg *allConfig) My() string {
 	if cfg.MyIP == "" {
 		return ""
 	}
-	return fmt.Sprintf("%s:%d", cfg.MyIP, cfg.Workerport)
+	return net.JoinHostPort(cfg.MyIP, strconv.Itoa(cfg.Workerport+cfg.PortOffset))
 }
 
 func (cfg *allConfig) changeTotalGroups() {
-	cfg.TotalGroups = prompt.InputInteger("The total number of groups?", cfg.TotalGroups, true, prompt.AtLeast2)
+	cfg.TotalGroups = prompt.InputInteger("The total number of groups?", fieldHelp("total groups"), cfg.TotalGroups, true, prompt.AtLeast2)
 	cfg.changeSelectedGroups()
 }
 
@@ -271,17 +434,54 @@ func (cfg *allConfig) changeSelectedGroups() {
 
 func (cfg *allConfig) changeGroupsText() {
 	validators := survey.ComposeValidators(prompt.GroupsRegexValidator, cfg.ensureGroupsRangeValidator())
-	cfg.Groups = prompt.InputString("Enter the groups for this server (comma separated ints and int ranges accepted)", cfg.Groups, validators)
+	cfg.Groups = prompt.InputString("Enter the groups for this server (comma separated ints and int ranges accepted)", fieldHelp("groups"), cfg.Groups, validators)
 }
 
 func (cfg *allConfig) changeGroupsMenu() {
 	// exclusive range where start is 0 and count is 2
-	selected := prompt.MultiSelectInts("Select the groups (must choose at least one option)\n<<space to select/deselect, arrows to move, enter when done>>", 0, cfg.TotalGroups)
+	defaults := []string{}
+	if cfg.Groups != "" {
+		defaults = strings.Split(cfg.Groups, ",")
+	}
+	selected := prompt.MultiSelectInts("Select the groups (must choose at least one option)\n<<space to select/deselect, arrows to move, enter when done>>", fieldHelp("groups"), 0, cfg.TotalGroups, defaults)
 	cfg.Groups = strings.Join(selected, ",")
 }
 
+// changeMyIP offers the host's interface addresses as a menu. When a peer is
+// configured the address on the route to the peer is pre-selected.
 func (cfg *allConfig) changeMyIP() {
-	cfg.MyIP = prompt.InputString("The IP of this server?", cfg.MyIP, prompt.IPv4Validator)
+	ips := localIPs()
+	if len(ips) == 0 {
+		cfg.changeMyIPManually()
+		return
+	}
+	defaultIP := cfg.MyIP
+	if defaultIP == "" && cfg.Peer() != "" {
+		defaultIP = routeIPTo(cfg.Peer())
+	}
+	if !containsString(ips, defaultIP) {
+		defaultIP = ips[0]
+	}
+	options := append(ips, enterIPManually)
+	choice := prompt.SelectString("The IP of this server?", fieldHelp("my"), options, defaultIP)
+	if choice == enterIPManually {
+		cfg.changeMyIPManually()
+		return
+	}
+	cfg.MyIP = choice
+}
+
+func (cfg *allConfig) changeMyIPManually() {
+	cfg.MyIP = prompt.InputString("The IP or hostname of this server?", fieldHelp("my"), cfg.MyIP, hostValidator())
+}
+
+// hostValidator validates peer and my hosts, resolving names when
+// --resolve-hosts is given.
+func hostValidator() survey.Validator {
+	if *resolveHosts {
+		return prompt.ResolvableHostValidator
+	}
+	return prompt.HostValidator
 }
 
 func (cfg *allConfig) bindallFlag() string {
@@ -303,7 +503,7 @@ func (cfg *allConfig) myFlag() string {
 
 func (cfg *allConfig) downloadAndInstallBinary() {
 	filename := "install_dgraph.sh"
-	if err := runCommand("curl", "https://nightly.dgraph.io", "-o", filename); err != nil {
+	if err := runCommand("curl", cfg.schema().installScriptURL, "-o", filename); err != nil {
 		panic(err)
 	}
 	err := os.Chmod(filename, 0777)
@@ -311,25 +511,44 @@ func (cfg *allConfig) downloadAndInstallBinary() {
 		panic(err)
 	}
 	defer os.Remove(filename)
-	if err := runCommand("./install_dgraph.sh"); err != nil {
+	if err := runCommand(append([]string{"./install_dgraph.sh"}, cfg.schema().installArgs...)...); err != nil {
 		panic(err)
 	}
 }
 
 func (cfg *allConfig) startDgraphCommand() string {
-	return fmt.Sprintf("/usr/local/bin/dgraph %s", cfg.configFlag())
+	command := cfg.schema().command
+	if command == "" {
+		return fmt.Sprintf("%s %s", dgraphBinary, cfg.configFlag())
+	}
+	return fmt.Sprintf("%s %s %s", dgraphBinary, command, cfg.configFlag())
 }
 
+// systemDUnit is the unit of the data server. It starts after the zero on
+// the same host, if there is one.
 func (cfg *allConfig) systemDUnit() string {
+	dependencies := []string{}
+	if cfg.RunZero {
+		dependencies = append(dependencies, zeroServiceName+".service")
+	}
+	directives := cfg.memoryMaxDirective() + cfg.logDirectives(cfg.serviceName())
+	return systemDUnit("Dgraph graph database", cfg.startDgraphCommand(), dependencies, directives)
+}
+
+// systemDUnit returns a unit that starts execStart once the network and the
+// dependencies are up. serviceDirectives are appended to the [Service] section.
+func systemDUnit(description string, execStart string, dependencies []string, serviceDirectives string) string {
+	wants := strings.Join(append([]string{"network-online.target"}, dependencies...), " ")
+	after := strings.Join(append([]string{"network.target", "network-online.target"}, dependencies...), " ")
 	return fmt.Sprintf(`
 [Unit]
-Description = Dgraph graph database
-Wants=network-online.target
-After=network.target network-online.target
+Description = %s
+Wants=%s
+After=%s
 
 [Service]	
 ExecStart = %s
-`, cfg.startDgraphCommand())
+%s`, description, wants, after, execStart, serviceDirectives)
 }
 
 func (cfg *allConfig) ensureGroupsRangeValidator() survey.Validator {
@@ -347,71 +566,73 @@ func (cfg *allConfig) ensureGroupsRangeValidator() survey.Validator {
 	}
 }
 
-func (cfg *allConfig) changeTrace() {
-	cfg.Trace = prompt.InputFloat64("The ratio of queries to trace", cfg.Trace, prompt.ZeroToOneOnly)
-}
-
-func (cfg *allConfig) changeGentlecommit() {
-	cfg.Gentlecommit = prompt.InputFloat64("Fraction of dirty posting lists to commit every few seconds", cfg.Gentlecommit, prompt.ZeroToOneOnly)
-}
-
-func (cfg *allConfig) changeMemoryMb() {
-	cfg.MemoryMb = prompt.InputFloat64("Estimated memory the process can take", cfg.MemoryMb, prompt.AtLeast1025)
-}
-
-func (cfg *allConfig) changeDebugMode() {
-	cfg.Debugmode = prompt.InputYesOrNo("Debug Mode?", cfg.Debugmode)
-}
-
-func (cfg *allConfig) changeIdx() {
-	cfg.Idx = prompt.InputInteger("RAFT ID that this server will use to join RAFT groups?", cfg.Idx, true, prompt.PositiveIntValidator)
-}
-
 func (cfg *allConfig) printConfigTable() {
-	yamlFilepath := path.Join(cfg.installDir, cfg.yamlFilename)
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Key", "Value", "Description", "Destination"})
-	data := [][]string{
-		[]string{"p", cfg.P, "Postings Files Directory", yamlFilepath},
-		[]string{"w", cfg.W, "Write-Ahead Logs Directory", yamlFilepath},
-		[]string{"export", cfg.Export, "Exports Directory", yamlFilepath},
-		[]string{"port", int2string(cfg.Port), "HTTP port", yamlFilepath},
-		[]string{"grpc_port", int2string(cfg.GrpcPort), "gRPC port", yamlFilepath},
-		[]string{"workerport", int2string(cfg.Workerport), "Internal worker port", yamlFilepath},
-		[]string{"idx", int2string(cfg.Idx), "Raft ID for joining groups", yamlFilepath},
-		[]string{"total groups", int2string(cfg.TotalGroups), "the total number of groups", "nil"},
-		[]string{"groups", cfg.Groups, "Groups for this server", yamlFilepath},
-		[]string{"memory_mb", float2string(cfg.MemoryMb), "Estimated Memory in MB", yamlFilepath},
-		[]string{"gentlecommit", float2string(cfg.Gentlecommit), "Dirty posting commit freq", yamlFilepath},
-		[]string{"trace", float2string(cfg.Trace), "Ratio of queries to trace", yamlFilepath},
-		[]string{"debugmode", bool2string(cfg.Debugmode), "Debug mode", yamlFilepath},
-		[]string{"bindall", bool2string(cfg.Bindall), cfg.serverStartsOn(), yamlFilepath},
-		[]string{"my", cfg.My(), "This server's IP:PORT", yamlFilepath},
-		[]string{"peer", cfg.Peer(), "Peer's IP:PORT", yamlFilepath},
-	}
+	table.SetHeader(summaryHeader)
 	table.SetAlignment(tablewriter.ALIGN_LEFT)
 	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
 	table.SetCenterSeparator("|")
-	table.AppendBulk(data) // Add Bulk Data
+	table.AppendBulk(cfg.summaryRows()) // Add Bulk Data
 	table.Render()
-	fmt.Printf("dgraph command is %s\n", cfg.startDgraphCommand())
+	if cfg.RunZero {
+		fmt.Printf("dgraph %s zero command is %s\n", cfg.DgraphVersion, cfg.startZeroCommand())
+	}
+	if cfg.RunAlpha {
+		fmt.Printf("dgraph %s command is %s\n", cfg.DgraphVersion, cfg.startDgraphCommand())
+	}
 }
 
 func (cfg *allConfig) createInstallDir() {
 	os.MkdirAll(cfg.installDir, os.ModePerm)
 }
 
-func (cfg *allConfig) createSubirs() {
-	os.MkdirAll(cfg.P, os.ModePerm)
-	os.MkdirAll(cfg.W, os.ModePerm)
-	os.MkdirAll(cfg.Export, os.ModePerm)
+// subdirs are the data directories by their name under installDir.
+func (cfg *allConfig) subdirs() map[string]*string {
+	return map[string]*string{"p": &cfg.P, "w": &cfg.W, "exports": &cfg.Export, "zw": &cfg.ZeroW}
+}
+
+// setSubdirs places the data directories under installDir.
+func (cfg *allConfig) setSubdirs() {
+	for name, dir := range cfg.subdirs() {
+		*dir = path.Join(cfg.installDir, name)
+	}
+}
+
+// moveSubdirs moves the data directories that are still in their place
+// under oldDir to installDir. Directories given elsewhere, e.g. with -p,
+// are kept.
+func (cfg *allConfig) moveSubdirs(oldDir string) {
+	for name, dir := range cfg.subdirs() {
+		if *dir == path.Join(oldDir, name) {
+			*dir = path.Join(cfg.installDir, name)
+		}
+	}
 }
 
-func (cfg *allConfig) serverStartsOn() string {
-	if cfg.Bindall {
-		return "Server host is 0.0.0.0"
+func (cfg *allConfig) createSubirs() {
+	if cfg.RunAlpha {
+		os.MkdirAll(cfg.P, os.ModePerm)
+		os.MkdirAll(cfg.W, os.ModePerm)
+		os.MkdirAll(cfg.Export, os.ModePerm)
+	}
+	if cfg.RunZero {
+		os.MkdirAll(cfg.ZeroW, os.ModePerm)
 	}
-	return "Server host is 127.0.0.1"
+}
+
+// plannedConfig is the configuration an install starts its prompts from:
+// the defaults of the release, changed by the values given as flags,
+// environment variables, answers, profile or last answers.
+func plannedConfig() allConfig {
+	cfg := defaultConfig()
+	cfg.setRecommendedMemoryMb()
+	cfg.applyOverrides()
+	cfg.schema().setDefaults(&cfg)
+	cfg.applyOverrides()
+	cfg.setSubdirs()
+	// subdirectories given as flags or answers win over the install dir
+	cfg.applyOverrides()
+	return cfg
 }
 
 // Install runs prompts for configuration files info and command-line flags,
@@ -426,50 +647,66 @@ func Install() {
 		log.Fatal(err)
 	}
 
-	cfg := defaultConfig()
-	if cfg.wantsToChangeInstallDir() {
-		cfg.changeInstallDir()
-	}
-	cfg.P = path.Join(cfg.installDir, "p")
-	cfg.W = path.Join(cfg.installDir, "w")
-	cfg.Export = path.Join(cfg.installDir, "exports")
-	if cfg.wantsToChangeSubdirectories() {
-		cfg.changeP()
-		cfg.changeW()
-		cfg.changeExport()
-	}
-	if cfg.wantsToChangePorts() {
-		cfg.changePort()
-		cfg.changeGrpcPort()
-		cfg.changeWorkerport()
-	}
-	if cfg.wantsToChangeEngine() {
-		cfg.changeMemoryMb()
-		cfg.changeDebugMode()
-		cfg.changeGentlecommit()
-		cfg.changeTrace()
-	}
-	if cfg.wantsToChangeCluster() {
-		cfg.Bindall = true
-		cfg.changeIdx()
-		if !cfg.isFirstServer() {
-			cfg.changePeer()
+	profile := *profileName
+	if profile == "" {
+		profile = chooseProfile()
+	}
+	if err := readProfile(profile); err != nil {
+		log.Fatal(err)
+	}
+	if !*ignoreLast {
+		if err := readLastAnswers(); err != nil {
+			fmt.Println(err)
+		}
+	}
+	cfg := plannedConfig()
+	prompt.RunSections(cfg.installSections())
+	if !prompt.Interactive {
+		if missing := cfg.unansweredFields(); len(missing) > 0 {
+			log.Fatalf("stdin is not a terminal and these settings have no valid value; give them as flags or in the -answers file: %s", strings.Join(missing, " "))
 		}
-		cfg.changeTotalGroups()
-		cfg.changeMyIP()
 	}
-	cfg.printConfigTable()
 
-	if cfg.wantsToCommitConfig() {
+	if cfg.reviewConfig() {
+		if err := cfg.writeLastAnswers(); err != nil {
+			fmt.Println(err)
+		}
 		fmt.Println("Installing...")
 		cfg.createInstallDir()
 		cfg.createSubirs()
+		cfg.createLogDir()
+		if cfg.GenerateCerts {
+			if err := cfg.generateCerts(nil); err != nil {
+				log.Fatal(err)
+			}
+		}
 		cfg.downloadAndInstallBinary()
-		cfg.writeConfigDotYaml()
-		cfg.writeSystemDUnit()
-		startDgraphService()
-		time.Sleep(time.Second)
-		statusDgraphService()
+		if cfg.RunZero {
+			cfg.writeZeroDotYaml()
+			cfg.writeZeroSystemDUnit()
+		}
+		if cfg.RunAlpha {
+			cfg.writeConfigDotYaml()
+			cfg.writeSystemDUnit()
+		}
+		if cfg.RunRatel {
+			cfg.writeRatelSystemDUnit()
+		}
+		if cfg.ScheduleExports {
+			cfg.writeExportSystemDUnits()
+		}
+		cfg.writeLogrotateConfig()
+		reloadDaemons()
+		for _, service := range cfg.serviceNames() {
+			startDgraphService(service)
+		}
+		if err := cfg.checkHealth(); err != nil {
+			fmt.Println(err)
+		}
+		if cfg.ScheduleExports {
+			enableExportTimer()
+		}
+		statusDgraphService(cfg.serviceNames()...)
 	}
 }
 
@@ -480,15 +717,36 @@ func reloadDaemons() {
 	}
 }
 
-func startDgraphService() {
-	err := runCommand("systemctl", "start", "dgraph")
+func startDgraphService(service string) {
+	err := runCommand("systemctl", "start", service)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func stopDgraphService(service string) {
+	err := runCommand("systemctl", "stop", service)
 	if err != nil {
 		log.Fatal(err)
 	}
 }
 
-func statusDgraphService() {
-	err := runCommand("systemctl", "status", "dgraph")
+// dgraphVersion returns the first line of `dgraph version` that mentions the version.
+func dgraphVersion() (string, error) {
+	output, err := runCommandOutput(dgraphBinary, "version")
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(strings.ToLower(line), "version") {
+			return strings.TrimSpace(line), nil
+		}
+	}
+	return output, nil
+}
+
+func statusDgraphService(services ...string) {
+	err := runCommand(append([]string{"systemctl", "status"}, services...)...)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+)
+
+// TestConfigDotYamlRoundTrip writes a TLS config.yaml for every release and
+// checks that reading it back, as relocate and certs do, rewrites it unchanged.
+func TestConfigDotYamlRoundTrip(t *testing.T) {
+	for _, version := range dgraphVersions() {
+		dir, err := ioutil.TempDir("", "dgraph_helper")
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg := defaultConfig()
+		cfg.DgraphVersion = version
+		cfg.schema().setDefaults(&cfg)
+		cfg.installDir = dir
+		cfg.setSubdirs()
+		cfg.PeerIP = "zero1"
+		cfg.MyIP = "10.0.0.2"
+		cfg.setGeneratedCertPaths()
+		cfg.TlsClientAuth = "REQUIREANDVERIFY"
+		cfg.writeConfigDotYaml()
+		want, err := ioutil.ReadFile(cfg.configDotYamlFilepath())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		read, err := readConfigDotYaml(cfg.configDotYamlFilepath())
+		if err != nil {
+			t.Fatalf("%s: %v", version, err)
+		}
+		got, err := read.toYAML()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s: config.yaml changed on rewrite\nwant:\n%s\ngot:\n%s", version, want, got)
+		}
+
+		read.rebase(dir, path.Join(dir, "moved"))
+		if !read.TlsOn || read.TlsCert != path.Join(dir, "moved", "tls", nodeCertFilename) {
+			t.Errorf("%s: TLS did not move with the install dir: on %v, cert %s", version, read.TlsOn, read.TlsCert)
+		}
+	}
+}
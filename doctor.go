@@ -0,0 +1,183 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+const (
+	checkPass = "pass"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+// recommendedOpenFiles is the ulimit -n dgraph recommends for production.
+const recommendedOpenFiles = 65535
+
+// minFreeDiskMb and lowFreeDiskMb are the thresholds for failing and
+// warning about the free space for posting lists and write-ahead logs.
+const (
+	minFreeDiskMb = 1024.0
+	lowFreeDiskMb = 10 * 1024.0
+)
+
+type checkResult struct {
+	name   string
+	status string
+	detail string
+}
+
+// Doctor checks the host for everything that makes an install fail or
+// perform badly and prints the results as a table. It exits with status 1
+// if any check fails. The checks use the install being planned with the
+// flags, environment variables, answers file, -profile and the last answers
+// like Install does, or the install of -config.
+func Doctor(args []string) {
+	flags := flag.NewFlagSet("doctor", flag.ExitOnError)
+	configPath := flags.String("config", "", "config.yaml of an installed dgraph to check instead of a planned install")
+	flags.Parse(args)
+
+	if *profileName != "" {
+		if err := readProfile(*profileName); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if !*ignoreLast {
+		if err := readLastAnswers(); err != nil {
+			fmt.Println(err)
+		}
+	}
+	cfg := plannedConfig()
+	if *configPath != "" {
+		var err error
+		if cfg, err = readConfigDotYaml(*configPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+	results := []checkResult{
+		checkSystemDPermissions(),
+		checkFreeDisk("p", cfg.P),
+		checkFreeDisk("w", cfg.W),
+		cfg.checkMemory(),
+		checkOpenFiles(),
+		checkDgraphBinary(),
+	}
+	results = append(results, cfg.checkPortsAvailable()...)
+	results = append(results, checkClockSync())
+	printCheckTable(results)
+	for _, result := range results {
+		if result.status == checkFail {
+			os.Exit(1)
+		}
+	}
+}
+
+func checkSystemDPermissions() checkResult {
+	name := fmt.Sprintf("write access to %s", systemDpath)
+	if err := ensurePermissions(); err != nil {
+		return checkResult{name, checkFail, err.Error()}
+	}
+	return checkResult{name, checkPass, ""}
+}
+
+func checkFreeDisk(key string, dir string) checkResult {
+	name := fmt.Sprintf("free disk for %s (%s)", key, dir)
+	freeMb, err := freeDiskMb(dir)
+	if err != nil {
+		return checkResult{name, checkFail, err.Error()}
+	}
+	detail := fmt.Sprintf("%s MB free", float2string(freeMb))
+	if freeMb < minFreeDiskMb {
+		return checkResult{name, checkFail, detail}
+	}
+	if freeMb < lowFreeDiskMb {
+		return checkResult{name, checkWarn, detail}
+	}
+	return checkResult{name, checkPass, detail}
+}
+
+func (cfg *allConfig) checkMemory() checkResult {
+	name := "memory for memory_mb"
+	info, err := meminfo()
+	if err != nil {
+		return checkResult{name, checkFail, err.Error()}
+	}
+	detail := fmt.Sprintf("memory_mb %s MB, total %s MB, available %s MB",
+		float2string(cfg.MemoryMb), float2string(info["MemTotal"]), float2string(info["MemAvailable"]))
+	if info["MemTotal"] < cfg.MemoryMb {
+		return checkResult{name, checkFail, detail}
+	}
+	if info["MemAvailable"] < cfg.MemoryMb {
+		return checkResult{name, checkWarn, detail}
+	}
+	return checkResult{name, checkPass, detail}
+}
+
+func checkOpenFiles() checkResult {
+	name := "open files limit (ulimit -n)"
+	limit, err := openFilesLimit()
+	if err != nil {
+		return checkResult{name, checkFail, err.Error()}
+	}
+	detail := fmt.Sprintf("%d (recommended at least %d)", limit, recommendedOpenFiles)
+	if limit < recommendedOpenFiles {
+		return checkResult{name, checkWarn, detail}
+	}
+	return checkResult{name, checkPass, detail}
+}
+
+func checkDgraphBinary() checkResult {
+	name := fmt.Sprintf("dgraph binary (%s)", dgraphBinary)
+	if _, err := os.Stat(dgraphBinary); err != nil {
+		return checkResult{name, checkWarn, "not installed yet (install will download it)"}
+	}
+	version, err := dgraphVersion()
+	if err != nil {
+		return checkResult{name, checkWarn, fmt.Sprintf("could not get version: %v", err)}
+	}
+	return checkResult{name, checkPass, version}
+}
+
+func (cfg *allConfig) checkPortsAvailable() []checkResult {
+	results := []checkResult{}
+	if err := cfg.ensureDistinctPorts(); err != nil {
+		results = append(results, checkResult{"distinct ports", checkFail, err.Error()})
+	}
+	for _, np := range cfg.namedPorts() {
+		name := fmt.Sprintf("%s %d available", np.name, np.port)
+		if portAvailable(np.port) {
+			results = append(results, checkResult{name, checkPass, ""})
+		} else {
+			results = append(results, checkResult{name, checkWarn, "in use by " + portHolder(np.port)})
+		}
+	}
+	return results
+}
+
+func checkClockSync() checkResult {
+	name := "clock synchronized"
+	synced, err := runCommandOutput("timedatectl", "show", "--property=NTPSynchronized", "--value")
+	if err != nil {
+		return checkResult{name, checkWarn, fmt.Sprintf("could not run timedatectl: %v", err)}
+	}
+	if synced != "yes" {
+		return checkResult{name, checkWarn, "the system clock is not synchronized (enable NTP with timedatectl set-ntp true)"}
+	}
+	return checkResult{name, checkPass, ""}
+}
+
+func printCheckTable(results []checkResult) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Check", "Status", "Detail"})
+	for _, result := range results {
+		table.Append([]string{result.name, result.status, result.detail})
+	}
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.Render()
+}
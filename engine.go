@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/AlecAivazis/survey"
+
+	"github.com/elbow-jason/dgraph_helper/prompt"
+)
+
+var postingTablesOptions = []string{"loadtoram", "memorymap", "nothing"}
+
+func (cfg *allConfig) changeAdvancedEngine() {
+	cfg.askFields(advancedEngineSection)
+	if cfg.schema().supports("cpu") && prompt.InputYesOrNo("Change dgraph's profiling config?", "Profiling writes pprof files and subgraph dumps for debugging. It slows dgraph down.", false) {
+		cfg.askFields(profilingSection)
+	}
+}
+
+func (cfg *allConfig) changePortOffset() {
+	validators := survey.ComposeValidators(prompt.NonNegativeIntValidator, cfg.portOffsetValidator())
+	cfg.PortOffset = prompt.InputInteger("Value added to all listening port numbers?", fieldHelp("port_offset"), cfg.PortOffset, true, validators)
+}
+
+// portOffsetValidator ensures the offset does not push any port past 65535.
+func (cfg *allConfig) portOffsetValidator() survey.Validator {
+	return func(answer interface{}) error {
+		offset, err := strconv.Atoi(answer.(string))
+		if err != nil {
+			return err
+		}
+		for _, port := range []int{cfg.Port, cfg.GrpcPort, cfg.Workerport} {
+			if port+offset > 65535 {
+				return fmt.Errorf("An offset of %d moves port %d past 65535.", offset, port)
+			}
+		}
+		return nil
+	}
+}
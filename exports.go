@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+)
+
+const exportServiceName = "dgraph-export"
+
+const exportTimerFilename = exportServiceName + ".timer"
+
+func (cfg *allConfig) exportURL() string {
+	scheme := "http"
+	if cfg.TlsOn {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://localhost:%d/admin/export", scheme, cfg.Port+cfg.PortOffset)
+}
+
+// exportCommand asks the data server to write an export into the Export directory.
+func (cfg *allConfig) exportCommand() string {
+	if cfg.TlsOn {
+		// the certificate may be self-signed and is not issued for localhost
+		return fmt.Sprintf("/usr/bin/curl -sSfk %s", cfg.exportURL())
+	}
+	return fmt.Sprintf("/usr/bin/curl -sSf %s", cfg.exportURL())
+}
+
+// collectExportCommand moves the loose files of an export into a directory of
+// their own. Releases that write one file per group leave several files per
+// export, releases that write a directory per export leave none, and then no
+// directory is created that pruneExportsCommand would count as an export.
+// systemd needs $$ and %% for a literal $ and %.
+func (cfg *allConfig) collectExportCommand() string {
+	loose := "find " + cfg.Export + " -maxdepth 1 -type f"
+	return "/bin/sh -c 'if [ -n \"$$(" + loose + ")\" ]; then dir=" + cfg.Export + "/export-$$(date +%%Y%%m%%d%%H%%M%%S)" +
+		" && mkdir -p $$dir && " + loose + " -exec mv -t $$dir {} +; fi'"
+}
+
+// pruneExportsCommand deletes all but the newest ExportRetention exports.
+func (cfg *allConfig) pruneExportsCommand() string {
+	return fmt.Sprintf("/bin/sh -c 'ls -1dt %s/*/ | tail -n +%d | xargs -r rm -rf --'", cfg.Export, cfg.ExportRetention+1)
+}
+
+func (cfg *allConfig) exportSystemDUnit() string {
+	return fmt.Sprintf(`
+[Unit]
+Description = Dgraph export
+Requires=%s.service
+After=%s.service
+
+[Service]
+Type = oneshot
+ExecStart = %s
+ExecStartPost = %s
+ExecStartPost = %s
+`, cfg.serviceName(), cfg.serviceName(), cfg.exportCommand(), cfg.collectExportCommand(), cfg.pruneExportsCommand())
+}
+
+func (cfg *allConfig) exportSystemDTimer() string {
+	return fmt.Sprintf(`
+[Unit]
+Description = Scheduled dgraph exports
+
+[Timer]
+OnCalendar = %s
+Persistent = true
+
+[Install]
+WantedBy = timers.target
+`, cfg.ExportSchedule)
+}
+
+func (cfg *allConfig) writeExportSystemDUnits() {
+	err := ioutil.WriteFile(unitFilepath(exportServiceName), []byte(cfg.exportSystemDUnit()), os.ModePerm)
+	if err != nil {
+		panic(err)
+	}
+	err = ioutil.WriteFile(path.Join(systemDpath, exportTimerFilename), []byte(cfg.exportSystemDTimer()), os.ModePerm)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func enableExportTimer() {
+	err := runCommand("systemctl", "enable", "--now", exportTimerFilename)
+	if err != nil {
+		panic(err)
+	}
+}
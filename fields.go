@@ -0,0 +1,1008 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/AlecAivazis/survey"
+
+	"github.com/elbow-jason/dgraph_helper/prompt"
+)
+
+// The install sections (see installSections) a field is asked in. They also
+// decide which roles a field applies to and which file it is written to.
+const (
+	releaseSection        = "release"
+	installDirSection     = "install dir"
+	zeroSection           = "zero"
+	subdirectoriesSection = "subdirectories"
+	portsSection          = "ports"
+	engineSection         = "engine"
+	advancedEngineSection = "advanced engine"
+	profilingSection      = "profiling"
+	clusterSection        = "cluster"
+	tlsSection            = "tls"
+	ratelSection          = "ratel"
+	exportsSection        = "exports"
+	logsSection           = "logs"
+)
+
+// configField describes one setting. The prompts, config.yaml and zero.yaml,
+// the summary rows, the command-line flags and the answers file are all
+// generated from configFields, so a new dgraph flag is one entry there.
+type configField struct {
+	key          string // name in the summary (before the schema renames it), the flags and answers files
+	yaml         string // key in config.yaml, or in zero.yaml for zeroSection; "" when not written to either
+	section      string
+	description  string // the Description column of the summary
+	help         string // shown when the user answers the prompt with ?
+	defaultValue string // parsed like a flag value; "" keeps the zero value
+	question     string // asked by askField; "" when change asks instead
+	options      []string
+	validator    survey.Validator
+	omitEmpty    bool // leave the zero value out of the yaml
+	secret       bool // masked in the summary
+	hidden       bool // not a row of the summary
+	// value returns a *string, *int, *float64 or *bool pointing into
+	// allConfig; it decides the field's type. nil for derived fields.
+	value func(cfg *allConfig) interface{}
+	// display formats a derived field, e.g. my from MyIP and the workerport.
+	display func(cfg *allConfig) string
+	// change asks for the field when a question is not enough.
+	change func(cfg *allConfig)
+	// destination is the file the field ends up in when it is not a yaml key.
+	destination func(cfg *allConfig) string
+	// applies hides the field, e.g. the tls files while TLS is off.
+	applies func(cfg *allConfig) bool
+}
+
+var configFields []configField
+
+func fieldByKey(key string) configField {
+	for _, field := range configFields {
+		if field.key == key {
+			return field
+		}
+	}
+	panic(fmt.Sprintf("unknown config field %q", key))
+}
+
+// fieldHelp returns the help text of key.
+func fieldHelp(key string) string {
+	return fieldByKey(key).help
+}
+
+// flagName is the name of the field's command-line flag and answers file key.
+func (field configField) flagName() string {
+	return strings.Replace(field.key, " ", "_", -1)
+}
+
+// get formats the field's value like the summary shows it.
+func (field configField) get(cfg *allConfig) string {
+	if field.display != nil {
+		return field.display(cfg)
+	}
+	switch value := field.value(cfg).(type) {
+	case *string:
+		return *value
+	case *int:
+		return int2string(*value)
+	case *float64:
+		return float2string(*value)
+	case *bool:
+		return bool2string(*value)
+	}
+	return ""
+}
+
+// set parses text into the field, checking it with the field's validator
+// when validate is true.
+func (field configField) set(cfg *allConfig, text string, validate bool) error {
+	if field.value == nil {
+		return fmt.Errorf("%s cannot be set directly", field.key)
+	}
+	if validate && field.validator != nil {
+		if err := field.validator(text); err != nil {
+			return fmt.Errorf("%s: %v", field.key, err)
+		}
+	}
+	if validate && len(field.options) > 0 && !containsString(field.options, text) {
+		return fmt.Errorf("%s: expected one of %s, got %s", field.key, strings.Join(field.options, ", "), text)
+	}
+	var err error
+	switch value := field.value(cfg).(type) {
+	case *string:
+		*value = text
+	case *int:
+		*value, err = strconv.Atoi(text)
+	case *float64:
+		*value, err = strconv.ParseFloat(text, 64)
+	case *bool:
+		*value, err = strconv.ParseBool(text)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %v", field.key, err)
+	}
+	return nil
+}
+
+// yamlValue returns the value written to the yaml, and false when
+// omitEmpty leaves it out.
+func (field configField) yamlValue(cfg *allConfig) (interface{}, bool) {
+	var value interface{}
+	empty := false
+	if field.value == nil {
+		text := field.display(cfg)
+		value, empty = text, text == ""
+	} else {
+		switch pointer := field.value(cfg).(type) {
+		case *string:
+			value, empty = *pointer, *pointer == ""
+		case *int:
+			value, empty = *pointer, *pointer == 0
+		case *float64:
+			value, empty = *pointer, *pointer == 0
+		case *bool:
+			value, empty = *pointer, !*pointer
+		}
+	}
+	return value, !(empty && field.omitEmpty)
+}
+
+func (cfg *allConfig) sectionApplies(section string) bool {
+	switch section {
+	case releaseSection, installDirSection, logsSection:
+		return true
+	case zeroSection:
+		return cfg.RunZero
+	case ratelSection:
+		return cfg.RunRatel
+	case exportsSection:
+		return cfg.RunAlpha && cfg.ScheduleExports
+	}
+	return cfg.RunAlpha
+}
+
+// fieldApplies is false for fields of roles this host does not run and for
+// keys the release rejects.
+func (cfg *allConfig) fieldApplies(field configField) bool {
+	if !cfg.sectionApplies(field.section) || !cfg.schema().supports(field.key) {
+		return false
+	}
+	return field.applies == nil || field.applies(cfg)
+}
+
+func (cfg *allConfig) fieldDestination(field configField) string {
+	switch {
+	case field.destination != nil:
+		return field.destination(cfg)
+	case field.yaml != "" && field.section == zeroSection:
+		return cfg.zeroDotYamlFilepath()
+	case field.yaml != "":
+		return cfg.configDotYamlFilepath()
+	}
+	return "nil"
+}
+
+// setFieldDefaults sets every field to its defaultValue.
+func (cfg *allConfig) setFieldDefaults() {
+	for _, field := range configFields {
+		if field.value == nil || field.defaultValue == "" {
+			continue
+		}
+		if err := field.set(cfg, field.defaultValue, false); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// askField asks the question of key with its current value as the default.
+// Keys the release rejects are not asked.
+func (cfg *allConfig) askField(key string) {
+	if !cfg.schema().supports(key) {
+		return
+	}
+	cfg.askQuestion(fieldByKey(key))
+}
+
+// askQuestion asks the question of field whether or not the release
+// writes it, for fields other settings are derived from.
+func (cfg *allConfig) askQuestion(field configField) {
+	validator := field.validator
+	if validator == nil {
+		validator = prompt.AlwaysValid
+	}
+	switch value := field.value(cfg).(type) {
+	case *string:
+		if len(field.options) > 0 {
+			*value = prompt.SelectString(field.question, field.help, field.options, *value)
+		} else {
+			*value = prompt.InputString(field.question, field.help, *value, validator)
+		}
+	case *int:
+		*value = prompt.InputInteger(field.question, field.help, *value, true, validator)
+	case *float64:
+		*value = prompt.InputFloat64(field.question, field.help, *value, validator)
+	case *bool:
+		*value = prompt.InputYesOrNo(field.question, field.help, *value)
+	}
+}
+
+// changeField runs the field's change method or asks its question. It
+// returns false for fields that are never prompted for.
+func (cfg *allConfig) changeField(field configField) bool {
+	switch {
+	case field.change != nil:
+		field.change(cfg)
+	case field.question != "":
+		cfg.askField(field.key)
+	default:
+		return false
+	}
+	return true
+}
+
+// askFields asks for the fields of section in registry order.
+func (cfg *allConfig) askFields(section string) {
+	asked := map[string]bool{}
+	for _, field := range configFields {
+		if field.section != section || !cfg.schema().supports(field.key) {
+			continue
+		}
+		if field.applies != nil && !field.applies(cfg) {
+			continue
+		}
+		if field.change != nil {
+			// several fields may share one change method
+			name := fmt.Sprintf("%p", field.change)
+			if asked[name] {
+				continue
+			}
+			asked[name] = true
+		}
+		cfg.changeField(field)
+	}
+}
+
+// summaryRows returns the key, value, description and destination of
+// every setting that applies to this host's roles and dgraph version,
+// with the release's names for renamed keys.
+func (cfg *allConfig) summaryRows() [][]string {
+	rows := [][]string{}
+	for _, field := range configFields {
+		if field.hidden || !cfg.fieldApplies(field) {
+			continue
+		}
+		value := field.get(cfg)
+		if field.secret && value != "" {
+			value = "********"
+		}
+		rows = append(rows, []string{cfg.schema().name(field.key), value, field.description, cfg.fieldDestination(field)})
+	}
+	return rows
+}
+
+// yamlParams returns the yaml keys and values of the fields written to
+// zero.yaml (zero true) or config.yaml (zero false).
+func (cfg *allConfig) yamlParams(zero bool) map[string]interface{} {
+	params := map[string]interface{}{}
+	for _, field := range configFields {
+		if field.yaml == "" || (field.section == zeroSection) != zero || !cfg.fieldApplies(field) {
+			continue
+		}
+		if value, ok := field.yamlValue(cfg); ok {
+			params[field.yaml] = value
+		}
+	}
+	return params
+}
+
+// setYAMLParams sets the fields of config.yaml from params, which use
+// allConfig's keys (see flagSchema.unapply).
+func (cfg *allConfig) setYAMLParams(params map[string]interface{}) error {
+	for _, field := range configFields {
+		if field.yaml == "" || field.section == zeroSection || field.value == nil {
+			continue
+		}
+		value, ok := params[field.yaml]
+		if !ok || value == nil {
+			continue
+		}
+		if err := field.set(cfg, fmt.Sprint(value), false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func versionValidator(answer interface{}) error {
+	_, err := schemaFor(answer.(string))
+	return err
+}
+
+// validHost checks a host when it is used, after -resolve-hosts is parsed.
+func validHost(answer interface{}) error {
+	return hostValidator()(answer)
+}
+
+func init() {
+	configFields = []configField{
+		{
+			key:          "dgraph version",
+			section:      releaseSection,
+			description:  "dgraph release",
+			help:         "The release decides which flags config.yaml may hold and which commands run. v0.8 is a single dgraph process; v1.0 and later split it into dgraph zero and a data server.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.DgraphVersion },
+			defaultValue: defaultDgraphVersion,
+			validator:    versionValidator,
+			change:       (*allConfig).changeDgraphVersion,
+			hidden:       true,
+		},
+		{
+			key:         "roles",
+			section:     releaseSection,
+			description: "Roles run on this host",
+			help:        "zero assigns groups and tablets to the data servers; every cluster needs at least one. The data server (server or alpha) stores and serves the data. A single-host install runs both.",
+			change:      (*allConfig).changeRoles,
+			hidden:      true,
+		},
+		{
+			key:         "run zero",
+			section:     releaseSection,
+			description: "Run dgraph zero",
+			help:        "Runs dgraph zero on this host (releases with zero only).",
+			value:       func(cfg *allConfig) interface{} { return &cfg.RunZero },
+			hidden:      true,
+		},
+		{
+			key:          "run alpha",
+			section:      releaseSection,
+			description:  "Run the data server",
+			help:         "Runs the data server (dgraph, dgraph server or dgraph alpha) on this host.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.RunAlpha },
+			defaultValue: "true",
+			hidden:       true,
+		},
+		{
+			key:          "install dir",
+			section:      installDirSection,
+			description:  "Base directory",
+			help:         "config.yaml and, unless you change them, the data directories go here. Pick a filesystem with room for the data and the exports.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.installDir },
+			defaultValue: "/var/lib/dgraph",
+			question:     "The directory to store data folders and config files",
+			hidden:       true,
+		},
+		{
+			key:          "zero idx",
+			yaml:         "idx",
+			section:      zeroSection,
+			description:  "Zero's Raft ID",
+			help:         "A positive number unique to every zero in the cluster.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.ZeroIdx },
+			defaultValue: "1",
+			question:     "RAFT ID of this zero?",
+			validator:    prompt.PositiveIntValidator,
+		},
+		{
+			key:          "zero replicas",
+			yaml:         "replicas",
+			section:      zeroSection,
+			description:  "Replicas of each group",
+			help:         "How many data servers serve each group. Use an odd number (1, 3, 5) so raft keeps a quorum.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.ZeroReplicas },
+			defaultValue: "1",
+			question:     "How many replicas of each group?",
+			validator:    prompt.PositiveIntValidator,
+		},
+		{
+			key:          "zero port_offset",
+			yaml:         "port_offset",
+			section:      zeroSection,
+			description:  "Added to zero's ports",
+			help:         "Shifts zero's ports (5080 grpc, 6080 http), e.g. to run several zeros on one host.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.ZeroPortOffset },
+			defaultValue: "0",
+			validator:    prompt.NonNegativeIntValidator,
+			change:       (*allConfig).changeZeroPortOffset,
+		},
+		{
+			key:         "zero wal",
+			yaml:        "wal",
+			section:     zeroSection,
+			description: "Zero's Write-Ahead Logs Directory",
+			help:        "Where zero keeps its raft log. It is small, but losing it means rebuilding the cluster membership.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.ZeroW },
+			question:    "The directory to store zero's write-ahead logs?",
+		},
+		{
+			key:         "zero my",
+			yaml:        "my",
+			section:     zeroSection,
+			description: "This zero's IP:PORT",
+			help:        "The address the other zeros and the data servers use to reach this zero.",
+			display:     func(cfg *allConfig) string { return cfg.ZeroMy() },
+			omitEmpty:   true,
+		},
+		{
+			key:         "zero first",
+			section:     zeroSection,
+			description: "First zero of the cluster",
+			help:        "The first zero starts the cluster. Every other zero joins through a healthy zero.",
+			hidden:      true,
+		},
+		{
+			key:         "zero peer",
+			yaml:        "peer",
+			section:     zeroSection,
+			description: "Peer zero's IP:PORT",
+			help:        "A healthy zero to join through. It only has to be reachable when this zero starts.",
+			display:     func(cfg *allConfig) string { return cfg.ZeroPeer() },
+			change:      (*allConfig).changeZeroPeer,
+			omitEmpty:   true,
+		},
+		{
+			key:         "zero peer ip",
+			section:     zeroSection,
+			description: "Peer zero's IP or hostname",
+			help:        "The host part of zero peer. Empty for the first zero.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.ZeroPeerIP },
+			validator:   validHost,
+			hidden:      true,
+		},
+		{
+			key:          "zero peer port",
+			section:      zeroSection,
+			description:  "Peer zero's port",
+			help:         "The grpc port of the peer zero.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.ZeroPeerPort },
+			defaultValue: strconv.Itoa(zeroGrpcPort),
+			validator:    prompt.PortValidator,
+			hidden:       true,
+		},
+		{
+			key:         "p",
+			yaml:        "p",
+			section:     subdirectoriesSection,
+			description: "Postings Files Directory",
+			help:        "Where the posting lists (the data itself) are stored. It grows with the data; put it on the fastest disk. Changing it later without `dgraph_helper relocate` starts an empty database.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.P },
+			change:      (*allConfig).changeP,
+		},
+		{
+			key:         "w",
+			yaml:        "w",
+			section:     subdirectoriesSection,
+			description: "Write-Ahead Logs Directory",
+			help:        "Where the raft write-ahead logs are stored. Writes are synced here first, so a separate fast disk helps write throughput.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.W },
+			change:      (*allConfig).changeW,
+		},
+		{
+			key:         "export",
+			yaml:        "export",
+			section:     subdirectoriesSection,
+			description: "Exports Directory",
+			help:        "Where /admin/export writes the RDF and schema files. Each export is about the size of the data, so leave room for the ones you keep.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.Export },
+			question:    "The directory to store exports?",
+		},
+		{
+			key:          "port",
+			yaml:         "port",
+			section:      portsSection,
+			description:  "HTTP port",
+			help:         "The port of the HTTP API, the health check and /admin. 1-65535; ports below 1024 need root.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.Port },
+			defaultValue: "8080",
+			question:     "The port to serve http?",
+			validator:    prompt.PortValidator,
+		},
+		{
+			key:          "grpc_port",
+			yaml:         "grpc_port",
+			section:      portsSection,
+			description:  "gRPC port",
+			help:         "The port the gRPC clients (dgo, pydgraph, dgraph-js) connect to. 1-65535.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.GrpcPort },
+			defaultValue: "9080",
+			question:     "The port to serve grpc?",
+			validator:    prompt.PortValidator,
+		},
+		{
+			key:          "workerport",
+			yaml:         "workerport",
+			section:      portsSection,
+			description:  "Internal worker port",
+			help:         "The port the servers of a cluster use to talk to each other. It must be reachable from the other servers but should not be exposed to clients.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.Workerport },
+			defaultValue: "12345",
+			question:     "The port for worker communication?",
+			validator:    prompt.PortValidator,
+		},
+		{
+			key:          "memory_mb",
+			yaml:         "memory_mb",
+			section:      engineSection,
+			description:  "Estimated Memory in MB",
+			help:         "How much memory dgraph aims to use for its caches. At least 1025; the default is half of the host's memory, which leaves room for the OS page cache. Too high and the OOM killer stops dgraph.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.MemoryMb },
+			defaultValue: "1025",
+			validator:    prompt.AtLeast1025,
+			change:       (*allConfig).changeMemoryMb,
+		},
+		{
+			key:         "MemoryMax",
+			section:     engineSection,
+			description: "systemd memory limit in MB (0 is none)",
+			help:        "A hard limit systemd enforces by killing dgraph when it uses more. It must be above memory_mb and should be at least 1.5 times memory_mb, or dgraph restarts under load.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.MemoryMax },
+			validator:   prompt.NonNegativeIntValidator,
+			change:      (*allConfig).changeMemoryMax,
+			destination: func(cfg *allConfig) string { return cfg.systemDUnitFilepath() },
+		},
+		{
+			key:         "debugmode",
+			yaml:        "debugmode",
+			section:     engineSection,
+			description: "Debug mode",
+			help:        "Logs extra information and returns debug details in responses. Slower and noisy; leave it off in production.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.Debugmode },
+			question:    "Debug Mode?",
+		},
+		{
+			key:          "gentlecommit",
+			yaml:         "gentlecommit",
+			section:      engineSection,
+			description:  "Dirty posting commit freq",
+			help:         "The fraction (0 to 1) of dirty posting lists committed to disk every few seconds. Higher values write more often and use less memory; lower values batch more writes.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.Gentlecommit },
+			defaultValue: "0.1",
+			question:     "Fraction of dirty posting lists to commit every few seconds",
+			validator:    prompt.ZeroToOneOnly,
+		},
+		{
+			key:          "trace",
+			yaml:         "trace",
+			section:      engineSection,
+			description:  "Ratio of queries to trace",
+			help:         "The fraction (0 to 1) of queries traced for /debug/requests. Tracing every query (1) costs noticeable CPU; keep it low in production.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.Trace },
+			defaultValue: "0.33",
+			question:     "The ratio of queries to trace",
+			validator:    prompt.ZeroToOneOnly,
+		},
+		{
+			key:          "pending",
+			yaml:         "pending",
+			section:      advancedEngineSection,
+			description:  "Pending queries limit",
+			help:         "Queries beyond this many waiting ones are rejected. Lower it to protect a small host from overload.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.Pending },
+			defaultValue: "1000",
+			question:     "Number of pending queries? (useful for rate limiting)",
+			validator:    prompt.PositiveIntValidator,
+		},
+		{
+			key:          "pending_proposals",
+			yaml:         "pending_proposals",
+			section:      advancedEngineSection,
+			description:  "Pending mutation proposals limit",
+			help:         "Mutations beyond this many waiting raft proposals are rejected. Lower it if mutations pile up memory.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.PendingProposals },
+			defaultValue: "2000",
+			question:     "Number of pending mutation proposals? (useful for rate limiting)",
+			validator:    prompt.PositiveIntValidator,
+		},
+		{
+			key:          "port_offset",
+			yaml:         "port_offset",
+			section:      advancedEngineSection,
+			description:  "Added to all listening ports",
+			help:         "Shifts every port, so several dgraph servers can share a host. No port may end up above 65535.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.PortOffset },
+			defaultValue: "0",
+			validator:    prompt.NonNegativeIntValidator,
+			change:       (*allConfig).changePortOffset,
+		},
+		{
+			key:          "posting_tables",
+			yaml:         "posting_tables",
+			section:      advancedEngineSection,
+			description:  "Posting tables storage",
+			help:         "loadtoram keeps the tables in memory (fastest, most RAM), memorymap maps them from disk, nothing reads them from disk on demand (least RAM, slowest).",
+			value:        func(cfg *allConfig) interface{} { return &cfg.PostingTables },
+			defaultValue: "loadtoram",
+			question:     "How to store the posting tables? (most to least RAM, best to worst performance)",
+			options:      postingTablesOptions,
+		},
+		{
+			key:          "sc",
+			yaml:         "sc",
+			section:      advancedEngineSection,
+			description:  "WAL entries before snapshot",
+			help:         "How many write-ahead log entries pile up before a snapshot truncates the log. Lower values shrink w and speed up restarts at the cost of more snapshots.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.Sc },
+			defaultValue: "1000",
+			question:     "Max pending entries in the write-ahead log before a snapshot is taken?",
+			validator:    prompt.PositiveIntValidator,
+		},
+		{
+			key:          "ui",
+			yaml:         "ui",
+			section:      advancedEngineSection,
+			description:  "User interface assets",
+			help:         "The directory the bundled UI is served from. Leave the default unless you installed the assets elsewhere.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.UI },
+			defaultValue: "/usr/local/share/dgraph/assets",
+			question:     "The directory which contains assets for the user interface?",
+		},
+		{
+			key:          "expand_edge",
+			yaml:         "expand_edge",
+			section:      advancedEngineSection,
+			description:  "Enable expand()",
+			help:         "expand(_all_) needs dgraph to track every predicate of a node, which costs some write throughput.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.ExpandEdge },
+			defaultValue: "true",
+			question:     "Enable the expand() feature?",
+		},
+		{
+			key:         "expose_trace",
+			yaml:        "expose_trace",
+			section:     advancedEngineSection,
+			description: "Remote trace endpoint",
+			help:        "Serves /debug/requests to other hosts. It shows query details, so keep it off on public networks.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.ExposeTrace },
+			question:    "Allow the trace endpoint to be accessed remotely?",
+		},
+		{
+			key:         "nomutations",
+			yaml:        "nomutations",
+			section:     advancedEngineSection,
+			description: "Disallow mutations",
+			help:        "Makes this server read-only, e.g. for a replica used for reporting.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.Nomutations },
+			question:    "Disallow mutations on this server?",
+		},
+		{
+			key:         "group_conf",
+			yaml:        "group_conf",
+			section:     advancedEngineSection,
+			description: "Group config file",
+			help:        "A file assigning predicates to groups. Leave empty to let dgraph hash them.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.GroupConf },
+			question:    "The group config file? (leave empty for none)",
+			validator:   prompt.OptionalFileExistsValidator,
+			omitEmpty:   true,
+		},
+		{
+			key:         "cpu",
+			yaml:        "cpu",
+			section:     profilingSection,
+			description: "CPU profile file",
+			help:        "Writes a pprof CPU profile to this file. Profiling slows dgraph down; only enable it while debugging.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.Cpu },
+			question:    "The file to write the cpu profile to? (leave empty to disable)",
+			omitEmpty:   true,
+		},
+		{
+			key:         "mem",
+			yaml:        "mem",
+			section:     profilingSection,
+			description: "Memory profile file",
+			help:        "Writes a pprof heap profile to this file when dgraph exits.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.Mem },
+			question:    "The file to write the memory profile to? (leave empty to disable)",
+			omitEmpty:   true,
+		},
+		{
+			key:         "block",
+			yaml:        "block",
+			section:     profilingSection,
+			description: "Block profiling rate",
+			help:        "Samples one blocking event per this many nanoseconds blocked. 0 disables block profiling.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.Block },
+			question:    "The block profiling rate? (0 disables it)",
+			validator:   prompt.NonNegativeIntValidator,
+			omitEmpty:   true,
+		},
+		{
+			key:         "dumpsg",
+			yaml:        "dumpsg",
+			section:     profilingSection,
+			description: "Subgraph dump directory",
+			help:        "Dumps the subgraph of every query here. Only for debugging; it fills the disk quickly.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.Dumpsg },
+			question:    "The directory to dump subgraphs to? (leave empty to disable)",
+			omitEmpty:   true,
+		},
+		{
+			key:          "idx",
+			yaml:         "idx",
+			section:      clusterSection,
+			description:  "Raft ID for joining groups",
+			help:         "A positive number unique to every server in the cluster. Reusing an ID makes two servers fight over the same raft membership.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.Idx },
+			defaultValue: "1",
+			question:     "RAFT ID that this server will use to join RAFT groups?",
+			validator:    prompt.PositiveIntValidator,
+		},
+		{
+			key:          "total groups",
+			section:      clusterSection,
+			description:  "the total number of groups",
+			help:         "How many groups the predicates are sharded into across the cluster. At least 2 (group 0 holds the membership information).",
+			value:        func(cfg *allConfig) interface{} { return &cfg.TotalGroups },
+			defaultValue: "2",
+			validator:    prompt.AtLeast2,
+			change:       (*allConfig).changeTotalGroups,
+		},
+		{
+			key:          "groups",
+			yaml:         "groups",
+			section:      clusterSection,
+			description:  "Groups for this server",
+			help:         "The groups this server serves, e.g. 0,1 or 0-3. Every group should be served by at least one server, ideally by an odd number for raft quorum.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.Groups },
+			defaultValue: "0,1",
+			validator:    prompt.GroupsRegexValidator,
+			change:       (*allConfig).changeSelectedGroups,
+		},
+		{
+			key:         "bindall",
+			yaml:        "bindall",
+			section:     clusterSection,
+			description: "Listen on all interfaces",
+			help:        "Whether dgraph listens on all interfaces (needed in a cluster) or only on localhost.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.Bindall },
+		},
+		{
+			key:         "my",
+			yaml:        "my",
+			section:     clusterSection,
+			description: "This server's IP:PORT",
+			help:        "The address the other servers use to reach this one. Use an address they can route to, not localhost.",
+			display:     func(cfg *allConfig) string { return cfg.My() },
+			change:      (*allConfig).changeMyIP,
+			omitEmpty:   true,
+		},
+		{
+			key:         "my ip",
+			section:     clusterSection,
+			description: "This server's IP or hostname",
+			help:        "The host part of my; the port is always the workerport.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.MyIP },
+			validator:   validHost,
+			hidden:      true,
+			// without my dgraph picks the address itself
+			applies: func(cfg *allConfig) bool { return cfg.MyIP != "" },
+		},
+		{
+			key:         "first server",
+			section:     clusterSection,
+			description: "First server of the cluster",
+			help:        "The first server starts the cluster alone. Every other server needs the address of a healthy peer to join.",
+			hidden:      true,
+		},
+		{
+			key:         "peer",
+			yaml:        "peer",
+			section:     clusterSection,
+			description: "Peer's IP:PORT",
+			help:        "A healthy member of the cluster to join through (a zero for v1.0 and later). It only has to be reachable when this server starts.",
+			display:     func(cfg *allConfig) string { return cfg.Peer() },
+			change:      (*allConfig).changePeer,
+			omitEmpty:   true,
+		},
+		{
+			key:         "peer ip",
+			section:     clusterSection,
+			description: "Peer's IP or hostname",
+			help:        "The host part of peer.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.PeerIP },
+			validator:   validHost,
+			hidden:      true,
+			applies:     func(cfg *allConfig) bool { return cfg.PeerIP != "" || cfg.schema().peerRequired },
+		},
+		{
+			key:          "peer port",
+			section:      clusterSection,
+			description:  "Peer's port",
+			help:         "The port part of peer: the workerport of a peer for v0.8, the grpc port of a zero for later releases.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.PeerPort },
+			defaultValue: "12345",
+			validator:    prompt.PortValidator,
+			hidden:       true,
+		},
+		{
+			key:         "tls.on",
+			yaml:        "tls.on",
+			section:     tlsSection,
+			description: "Use TLS with clients",
+			help:        "Encrypts the HTTP and gRPC connections of clients. Clients then need the CA certificate to verify this server.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.TlsOn },
+			question:    "Use TLS connections with clients?",
+			change:      (*allConfig).changeTLS,
+		},
+		{
+			key:         "tls.generate",
+			section:     tlsSection,
+			description: "Generate certificates",
+			help:        "Creates a local CA and a server certificate under the install directory (see `dgraph_helper certs`). Pick no to use certificates from your own CA.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.GenerateCerts },
+			change:      (*allConfig).changeGenerateCerts,
+			hidden:      true,
+		},
+		{
+			key:         "tls_dir",
+			yaml:        "tls_dir",
+			section:     tlsSection,
+			description: "Directory with ca.crt, node.crt and node.key",
+			help:        "Releases from v1.1 read their certificates from one directory, which must hold ca.crt, node.crt and node.key. It is the directory of the certificate file.",
+			display:     func(cfg *allConfig) string { return cfg.tlsCertDir() },
+			change:      (*allConfig).changeTLS,
+			applies:     func(cfg *allConfig) bool { return cfg.TlsOn && cfg.schema().tlsDir },
+		},
+		{
+			key:         "tls.cert",
+			yaml:        "tls.cert",
+			section:     tlsSection,
+			description: "Certificate file",
+			help:        "The PEM server certificate, including any intermediate certificates.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.TlsCert },
+			validator:   prompt.FileExistsValidator,
+			change:      (*allConfig).changeTlsKeyPair,
+			applies:     func(cfg *allConfig) bool { return cfg.TlsOn },
+		},
+		{
+			key:         "tls.cert_key",
+			yaml:        "tls.cert_key",
+			section:     tlsSection,
+			description: "Certificate key file",
+			help:        "The PEM private key of the certificate. It is checked against the certificate before continuing.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.TlsCertKey },
+			validator:   prompt.FileExistsValidator,
+			change:      (*allConfig).changeTlsKeyPair,
+			applies:     func(cfg *allConfig) bool { return cfg.TlsOn },
+		},
+		{
+			key:         "tls.cert_key_passphrase",
+			yaml:        "tls.cert_key_passphrase",
+			section:     tlsSection,
+			description: "Certificate key passphrase",
+			help:        "Only needed for an encrypted key. It is written to config.yaml, which is then made readable by root only.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.TlsCertKeyPassphrase },
+			change:      (*allConfig).changeTlsKeyPair,
+			applies:     func(cfg *allConfig) bool { return cfg.TlsOn },
+			omitEmpty:   true,
+			secret:      true,
+		},
+		{
+			key:         "tls.ca_certs",
+			yaml:        "tls.ca_certs",
+			section:     tlsSection,
+			description: "CA certs file",
+			help:        "The CA certificates used to verify client certificates. Only needed with client authentication.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.TlsCaCerts },
+			question:    "The CA certs file? (leave empty for none)",
+			validator:   prompt.OptionalFileExistsValidator,
+			applies:     func(cfg *allConfig) bool { return cfg.TlsOn },
+			omitEmpty:   true,
+		},
+		{
+			key:         "tls.client_auth",
+			yaml:        "tls.client_auth",
+			section:     tlsSection,
+			description: "TLS client authentication",
+			help:        "REQUEST asks for a client certificate, REQUIREANY requires one, VERIFYIFGIVEN verifies it when given, REQUIREANDVERIFY requires a certificate signed by the CA certs.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.TlsClientAuth },
+			change:      (*allConfig).changeTlsClientAuth,
+			applies:     func(cfg *allConfig) bool { return cfg.TlsOn },
+			omitEmpty:   true,
+		},
+		{
+			key:          "tls.min_version",
+			yaml:         "tls.min_version",
+			section:      tlsSection,
+			description:  "TLS min version",
+			help:         "The oldest TLS version accepted. TLS10 is only needed for very old clients.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.TlsMinVersion },
+			defaultValue: "TLS11",
+			change:       (*allConfig).changeTlsVersions,
+			applies:      func(cfg *allConfig) bool { return cfg.TlsOn },
+		},
+		{
+			key:          "tls.max_version",
+			yaml:         "tls.max_version",
+			section:      tlsSection,
+			description:  "TLS max version",
+			help:         "The newest TLS version offered. It cannot be lower than the min version.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.TlsMaxVersion },
+			defaultValue: "TLS12",
+			change:       (*allConfig).changeTlsVersions,
+			applies:      func(cfg *allConfig) bool { return cfg.TlsOn },
+		},
+		{
+			key:         "tls.use_system_ca",
+			yaml:        "tls.use_system_ca",
+			section:     tlsSection,
+			description: "Include system CA into CA certs",
+			help:        "Also trusts the host's CA bundle when verifying client certificates.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.TlsUseSystemCa },
+			question:    "Include the system CA into CA certs?",
+			applies:     func(cfg *allConfig) bool { return cfg.TlsOn },
+		},
+		{
+			key:         "run ratel",
+			section:     ratelSection,
+			description: "Run the Ratel UI",
+			help:        "Runs the Ratel UI as its own service (releases with ratel only).",
+			value:       func(cfg *allConfig) interface{} { return &cfg.RunRatel },
+			hidden:      true,
+		},
+		{
+			key:          "ratel port",
+			section:      ratelSection,
+			description:  "Ratel UI port",
+			help:         "The port the Ratel web UI is served on. 1-65535.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.RatelPort },
+			defaultValue: "8000",
+			question:     "The port to serve the Ratel UI?",
+			validator:    prompt.PortValidator,
+			destination:  func(cfg *allConfig) string { return unitFilepath(ratelServiceName) },
+		},
+		{
+			key:         "ratel addr",
+			section:     ratelSection,
+			description: "dgraph address Ratel talks to",
+			help:        "The HTTP address of the data server on this host.",
+			display:     func(cfg *allConfig) string { return cfg.ratelAddr() },
+			destination: func(cfg *allConfig) string { return unitFilepath(ratelServiceName) },
+		},
+		{
+			key:         "schedule exports",
+			section:     exportsSection,
+			description: "Schedule exports",
+			help:        "Installs a systemd timer that exports the data on a schedule and deletes old exports.",
+			value:       func(cfg *allConfig) interface{} { return &cfg.ScheduleExports },
+			hidden:      true,
+		},
+		{
+			key:          "export schedule",
+			section:      exportsSection,
+			description:  "When exports run (OnCalendar)",
+			help:         "A systemd OnCalendar expression, e.g. daily, weekly or *-*-* 03:00:00. Check one with `systemd-analyze calendar`.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.ExportSchedule },
+			defaultValue: "daily",
+			question:     "When to export? (systemd OnCalendar, e.g. daily or *-*-* 03:00:00)",
+			validator:    prompt.CalendarValidator,
+			destination:  func(cfg *allConfig) string { return path.Join(systemDpath, exportTimerFilename) },
+		},
+		{
+			key:          "export retention",
+			section:      exportsSection,
+			description:  "Exports kept",
+			help:         "Older exports are deleted after each run. Keep enough to go back past a bad mutation you might notice late.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.ExportRetention },
+			defaultValue: "7",
+			question:     "How many exports to keep?",
+			validator:    prompt.PositiveIntValidator,
+			destination:  func(cfg *allConfig) string { return unitFilepath(exportServiceName) },
+		},
+		{
+			key:          "logs",
+			section:      logsSection,
+			description:  "Log destination",
+			help:         "journald keeps the logs in the journal (see journalctl). files appends them to <install dir>/logs, rotated by logrotate, and needs systemd 240 or newer.",
+			value:        func(cfg *allConfig) interface{} { return &cfg.LogDestination },
+			display:      func(cfg *allConfig) string { return cfg.logTarget() },
+			defaultValue: logToJournald,
+			question:     "Where should dgraph log?",
+			options:      logDestinationOptions,
+			destination:  func(cfg *allConfig) string { return cfg.logTargetFile() },
+		},
+	}
+}
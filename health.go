@@ -0,0 +1,68 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// healthTimeout is how long a freshly started service has to become healthy.
+const healthTimeout = 30 * time.Second
+
+// waitForHealth polls url until it answers 200 OK or timeout passes.
+func waitForHealth(url string, timeout time.Duration) error {
+	client := &http.Client{
+		Timeout: 2 * time.Second,
+		Transport: &http.Transport{
+			// only localhost is checked, and dgraph may use a self-signed certificate
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	deadline := time.Now().Add(timeout)
+	var lastErr error
+	for time.Now().Before(deadline) {
+		resp, err := client.Get(url)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+			err = fmt.Errorf("%s answered %s", url, resp.Status)
+		}
+		lastErr = err
+		time.Sleep(time.Second)
+	}
+	return fmt.Errorf("%s did not become healthy within %s: %v", url, timeout, lastErr)
+}
+
+func (cfg *allConfig) healthURL() string {
+	scheme := "http"
+	if cfg.TlsOn {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://localhost:%d/health", scheme, cfg.Port+cfg.PortOffset)
+}
+
+// checkHealth waits for each service this host runs to become healthy.
+func (cfg *allConfig) checkHealth() error {
+	if cfg.RunZero {
+		if err := waitForHealth(cfg.zeroHealthURL(), healthTimeout); err != nil {
+			return err
+		}
+		fmt.Printf("%s is healthy\n", zeroServiceName)
+	}
+	if cfg.RunAlpha {
+		if err := waitForHealth(cfg.healthURL(), healthTimeout); err != nil {
+			return err
+		}
+		fmt.Printf("%s is healthy\n", cfg.serviceName())
+	}
+	if cfg.RunRatel {
+		if err := waitForHealth(cfg.ratelHealthURL(), healthTimeout); err != nil {
+			return err
+		}
+		fmt.Printf("%s is healthy\n", ratelServiceName)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// meminfo returns the fields of /proc/meminfo in megabytes, keyed by name
+// (e.g. "MemTotal", "MemAvailable").
+func meminfo() (map[string]float64, error) {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info := map[string]float64{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// lines look like "MemTotal:       16307428 kB"
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		kb, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			continue
+		}
+		info[strings.TrimSuffix(fields[0], ":")] = kb / 1024
+	}
+	return info, scanner.Err()
+}
+
+// freeDiskMb returns the megabytes available to unprivileged users on the
+// filesystem that holds dir. dir does not have to exist yet; its closest
+// existing parent is used instead.
+func freeDiskMb(dir string) (float64, error) {
+	dir = nearestExistingDir(dir)
+	var stat unix.Statfs_t
+	if err := unix.Statfs(dir, &stat); err != nil {
+		return 0, fmt.Errorf("Could not stat %s: %v", dir, err)
+	}
+	return float64(stat.Bavail) * float64(stat.Bsize) / (1024 * 1024), nil
+}
+
+func nearestExistingDir(dir string) string {
+	for {
+		if _, err := os.Stat(dir); err == nil || dir == "/" || dir == "." {
+			return dir
+		}
+		dir = path.Dir(dir)
+	}
+}
+
+// openFilesLimit returns the soft limit for open file descriptors (ulimit -n).
+func openFilesLimit() (uint64, error) {
+	var limit unix.Rlimit
+	if err := unix.Getrlimit(unix.RLIMIT_NOFILE, &limit); err != nil {
+		return 0, err
+	}
+	return limit.Cur, nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"regexp"
+)
+
+const (
+	logToJournald = "journald"
+	logToFiles    = "files"
+)
+
+var logDestinationOptions = []string{logToJournald, logToFiles}
+
+const logrotateFilepath = "/etc/logrotate.d/dgraph"
+
+// logFileRegex finds the log file in a unit that logs to files.
+var logFileRegex = regexp.MustCompile(`(?m)^StandardOutput\s*=\s*append:(\S+)$`)
+
+// logTarget is where the logs can be found: the journal or the log directory.
+func (cfg *allConfig) logTarget() string {
+	if cfg.LogDestination == logToFiles {
+		return cfg.logDir()
+	}
+	return logToJournald
+}
+
+// logTargetFile is the file that configures the log destination.
+func (cfg *allConfig) logTargetFile() string {
+	if cfg.LogDestination == logToFiles {
+		return logrotateFilepath
+	}
+	return "journalctl"
+}
+
+func (cfg *allConfig) logDir() string {
+	return path.Join(cfg.installDir, "logs")
+}
+
+func (cfg *allConfig) logFilepath(service string) string {
+	return path.Join(cfg.logDir(), service+".log")
+}
+
+// logDirectives sends the output of service to the journal or to its log file.
+func (cfg *allConfig) logDirectives(service string) string {
+	if cfg.LogDestination == logToFiles {
+		logFile := cfg.logFilepath(service)
+		return fmt.Sprintf("StandardOutput = append:%s\nStandardError = append:%s\n", logFile, logFile)
+	}
+	return "StandardOutput = journal\nStandardError = journal\n"
+}
+
+// logrotateConfig rotates the log files daily. systemd keeps them open,
+// so they are truncated in place instead of being moved.
+func (cfg *allConfig) logrotateConfig() string {
+	return fmt.Sprintf(`%s/*.log {
+    daily
+    rotate 7
+    compress
+    delaycompress
+    missingok
+    notifempty
+    copytruncate
+}
+`, cfg.logDir())
+}
+
+func (cfg *allConfig) createLogDir() {
+	if cfg.LogDestination == logToFiles {
+		os.MkdirAll(cfg.logDir(), os.ModePerm)
+	}
+}
+
+func (cfg *allConfig) writeLogrotateConfig() {
+	if cfg.LogDestination != logToFiles {
+		return
+	}
+	err := ioutil.WriteFile(logrotateFilepath, []byte(cfg.logrotateConfig()), 0644)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Logs shows the logs of an installed dgraph service from the journal or
+// from its log file, whichever its unit writes to.
+func Logs(args []string) {
+	flags := flag.NewFlagSet("logs", flag.ExitOnError)
+	follow := flags.Bool("f", false, "keep printing new log lines")
+	since := flags.String("since", "", "only show lines since this time, e.g. \"1h ago\" or \"2017-08-01 12:00\" (journald only)")
+	service := flags.String("service", "", "the service to show (default: the data server)")
+	flags.Parse(args)
+
+	unit := *service
+	if unit == "" {
+		unit = installedDataServer()
+	}
+	if unit == "" {
+		log.Fatal("No dgraph service is installed.")
+	}
+	unitText, err := ioutil.ReadFile(unitFilepath(unit))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if match := logFileRegex.FindSubmatch(unitText); match != nil {
+		if *since != "" {
+			fmt.Fprintf(os.Stderr, "%s logs to %s, --since is ignored.\n", unit, match[1])
+		}
+		tail := []string{"tail", "-n", "100"}
+		if *follow {
+			tail = append(tail, "-F")
+		}
+		runCommand(append(tail, string(match[1]))...)
+		return
+	}
+	journalctl := []string{"journalctl", "--unit", unit}
+	if *follow {
+		journalctl = append(journalctl, "--follow")
+	}
+	if *since != "" {
+		journalctl = append(journalctl, "--since", *since)
+	}
+	runCommand(journalctl...)
+}
+
+// installedDataServer returns the installed unit of the data server, if any.
+func installedDataServer() string {
+	for _, service := range []string{"dgraph", "dgraph-server", "dgraph-alpha", zeroServiceName} {
+		if _, err := os.Stat(unitFilepath(service)); err == nil {
+			return service
+		}
+	}
+	return ""
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+
+	"github.com/AlecAivazis/survey"
+
+	"github.com/elbow-jason/dgraph_helper/prompt"
+)
+
+// memoryFraction is the share of the host's total memory proposed as memory_mb.
+// dgraph uses more than memory_mb at peak, so half leaves room for that and the OS.
+const memoryFraction = 0.5
+
+// memoryMaxHeadroom is how far MemoryMax should be above memory_mb:
+// dgraph uses more than memory_mb at peak.
+const memoryMaxHeadroom = 1.5
+
+// minMemoryMb is the smallest memory_mb dgraph accepts.
+const minMemoryMb = 1025.00
+
+// recommendedMemoryMb proposes memory_mb as a fraction of totalMb,
+// but never less than dgraph's minimum.
+func recommendedMemoryMb(totalMb float64) float64 {
+	return math.Max(minMemoryMb, math.Floor(totalMb*memoryFraction))
+}
+
+// setRecommendedMemoryMb sets MemoryMb from the host's total memory.
+// MemoryMb keeps its default if /proc/meminfo cannot be read.
+func (cfg *allConfig) setRecommendedMemoryMb() {
+	info, err := meminfo()
+	if err != nil {
+		return
+	}
+	cfg.MemoryMb = recommendedMemoryMb(info["MemTotal"])
+}
+
+func (cfg *allConfig) changeMemoryMb() {
+	info, err := meminfo()
+	message := "Estimated memory the process can take"
+	if err == nil {
+		message = fmt.Sprintf("Estimated memory the process can take (host has %s MB total, %s MB available)",
+			float2string(info["MemTotal"]), float2string(info["MemAvailable"]))
+	}
+	for {
+		cfg.MemoryMb = prompt.InputFloat64(message, fieldHelp("memory_mb"), cfg.MemoryMb, prompt.AtLeast1025)
+		if err != nil || cfg.MemoryMb <= info["MemAvailable"] {
+			return
+		}
+		warning := fmt.Sprintf("%s MB is more than the %s MB available. Use it anyway?",
+			float2string(cfg.MemoryMb), float2string(info["MemAvailable"]))
+		if prompt.InputYesOrNo(warning, fieldHelp("memory_mb"), false) {
+			return
+		}
+		if !prompt.Interactive {
+			log.Fatalf("Give a lower -memory_mb, or answer y to use %s MB anyway.", float2string(cfg.MemoryMb))
+		}
+	}
+}
+
+// changeMemoryMax asks whether systemd should cap dgraph's memory with MemoryMax=.
+func (cfg *allConfig) changeMemoryMax() {
+	if !prompt.InputYesOrNo("Limit dgraph's memory in its systemd unit (MemoryMax)?", fieldHelp("MemoryMax"), cfg.MemoryMax > 0) {
+		cfg.MemoryMax = 0
+		return
+	}
+	if cfg.MemoryMax == 0 {
+		cfg.MemoryMax = cfg.defaultMemoryMax()
+	}
+	validators := survey.ComposeValidators(prompt.PositiveIntValidator, cfg.aboveMemoryMbValidator())
+	for {
+		cfg.MemoryMax = prompt.InputInteger("The most memory in MB systemd lets dgraph use?", fieldHelp("MemoryMax"), cfg.MemoryMax, true, validators)
+		if float64(cfg.MemoryMax) >= cfg.MemoryMb*memoryMaxHeadroom {
+			return
+		}
+		warning := fmt.Sprintf("%d MB leaves little room above memory_mb (%s MB); dgraph uses more at peak and systemd would kill it. Use it anyway?",
+			cfg.MemoryMax, float2string(cfg.MemoryMb))
+		if prompt.InputYesOrNo(warning, fieldHelp("MemoryMax"), false) {
+			return
+		}
+		if !prompt.Interactive {
+			log.Fatalf("Give a -MemoryMax of at least %d, or answer y to use %d MB anyway.", int(cfg.MemoryMb*memoryMaxHeadroom), cfg.MemoryMax)
+		}
+	}
+}
+
+// aboveMemoryMbValidator rejects a MemoryMax that is not above memory_mb.
+func (cfg *allConfig) aboveMemoryMbValidator() survey.Validator {
+	return func(answer interface{}) error {
+		memoryMax, err := strconv.Atoi(answer.(string))
+		if err == nil && float64(memoryMax) <= cfg.MemoryMb {
+			return fmt.Errorf("MemoryMax must be above memory_mb (%s MB), or systemd kills dgraph under its normal load", float2string(cfg.MemoryMb))
+		}
+		return nil
+	}
+}
+
+// defaultMemoryMax is twice memory_mb, capped at the host's total memory.
+func (cfg *allConfig) defaultMemoryMax() int {
+	memoryMax := cfg.MemoryMb * 2
+	if info, err := meminfo(); err == nil && info["MemTotal"] < memoryMax {
+		memoryMax = info["MemTotal"]
+	}
+	return int(memoryMax)
+}
+
+func (cfg *allConfig) memoryMaxDirective() string {
+	if cfg.MemoryMax == 0 {
+		return ""
+	}
+	return fmt.Sprintf("MemoryMax = %dM\n", cfg.MemoryMax)
+}
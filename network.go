@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net"
+)
+
+// enterIPManually is the menu option that falls back to typing an IP by hand.
+const enterIPManually = "Other (enter manually)"
+
+// localIPs returns the IPv4 and global IPv6 addresses of the host's interfaces
+// that are up and are not loopback interfaces. Link-local addresses are left
+// out since they are not usable without a zone.
+func localIPs() []string {
+	ips := []string{}
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return ips
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.IsLinkLocalUnicast() {
+				continue
+			}
+			ips = append(ips, ipNet.IP.String())
+		}
+	}
+	return ips
+}
+
+// routeIPTo returns the local IP the kernel would use to reach addr
+// (host:port). Dialing udp sends no packets, it only picks a route.
+// Returns "" if there is no route.
+func routeIPTo(addr string) string {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return ""
+	}
+	defer conn.Close()
+	localAddr, ok := conn.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		return ""
+	}
+	return localAddr.IP.String()
+}
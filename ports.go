@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/elbow-jason/dgraph_helper/prompt"
+)
+
+// tcpListenState is the state column value of a listening socket in /proc/net/tcp.
+const tcpListenState = "0A"
+
+type namedPort struct {
+	name string
+	port int
+}
+
+// namedPorts returns the ports of the roles this host runs, offsets included.
+func (cfg *allConfig) namedPorts() []namedPort {
+	ports := []namedPort{}
+	if cfg.RunZero {
+		ports = append(ports,
+			namedPort{"zero grpc port", zeroGrpcPort + cfg.ZeroPortOffset},
+			namedPort{"zero http port", zeroHTTPPort + cfg.ZeroPortOffset},
+		)
+	}
+	if cfg.RunAlpha {
+		ports = append(ports,
+			namedPort{"port", cfg.Port + cfg.PortOffset},
+			namedPort{"grpc_port", cfg.GrpcPort + cfg.PortOffset},
+			namedPort{"workerport", cfg.Workerport + cfg.PortOffset},
+		)
+	}
+	if cfg.RunRatel {
+		ports = append(ports, namedPort{"ratel port", cfg.RatelPort})
+	}
+	return ports
+}
+
+// ensureDistinctPorts returns an error if any two of dgraph's ports are equal.
+func (cfg *allConfig) ensureDistinctPorts() error {
+	seen := map[int]string{}
+	for _, np := range cfg.namedPorts() {
+		if other, ok := seen[np.port]; ok {
+			return fmt.Errorf("%s and %s are both set to %d. Each port must be different.", other, np.name, np.port)
+		}
+		seen[np.port] = np.name
+	}
+	return nil
+}
+
+// busyPorts returns a message for each of dgraph's ports that cannot be bound.
+func (cfg *allConfig) busyPorts() []string {
+	messages := []string{}
+	for _, np := range cfg.namedPorts() {
+		if portAvailable(np.port) {
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("%s %d is in use by %s", np.name, np.port, portHolder(np.port)))
+	}
+	return messages
+}
+
+// checkPorts reports port conflicts and returns true if the ports may be used.
+// Ports that are already bound (e.g. by a previous dgraph install) only
+// need the user's confirmation.
+func (cfg *allConfig) checkPorts() bool {
+	if err := cfg.ensureDistinctPorts(); err != nil {
+		fmt.Println(err)
+		return false
+	}
+	busy := cfg.busyPorts()
+	if len(busy) == 0 {
+		return true
+	}
+	for _, message := range busy {
+		fmt.Println(message)
+	}
+	return prompt.InputYesOrNo("Some ports are already in use. Continue anyway?", "Answer no to pick other ports. dgraph fails to start while another process holds one of its ports.", false)
+}
+
+// ensurePorts asks for other ports until checkPorts accepts them. Reading
+// answers from stdin the ports cannot be asked again, so it exits instead.
+func (cfg *allConfig) ensurePorts() {
+	for !cfg.checkPorts() {
+		if !prompt.Interactive {
+			log.Fatalf("Give other ports with %s, or answer y to continue with ports in use.", strings.Join(cfg.portFlags(), " "))
+		}
+		cfg.changePorts()
+	}
+}
+
+// portFlags are the flags of the ports changePorts asks for.
+func (cfg *allConfig) portFlags() []string {
+	flags := []string{}
+	if cfg.RunZero {
+		flags = append(flags, "-zero_port_offset")
+	}
+	if cfg.RunRatel {
+		flags = append(flags, "-ratel_port")
+	}
+	if cfg.RunAlpha && cfg.schema().supports("port") {
+		flags = append(flags, "-port", "-grpc_port", "-workerport")
+	} else if cfg.RunAlpha {
+		flags = append(flags, "-port_offset")
+	}
+	return flags
+}
+
+// portAvailable tries to bind port on all interfaces.
+func portAvailable(port int) bool {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
+
+// portHolder describes the process listening on port, e.g. "dgraph (pid 42)".
+// It matches the socket inodes in /proc/net/tcp{,6} against the open fds
+// of every process, so naming processes of other users requires root.
+func portHolder(port int) string {
+	inodes := listeningInodes(port)
+	if len(inodes) == 0 {
+		return "an unknown process"
+	}
+	procs, err := ioutil.ReadDir("/proc")
+	if err != nil {
+		return "an unknown process"
+	}
+	for _, proc := range procs {
+		pid, err := strconv.Atoi(proc.Name())
+		if err != nil {
+			continue
+		}
+		fdDir := path.Join("/proc", proc.Name(), "fd")
+		fds, err := ioutil.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(path.Join(fdDir, fd.Name()))
+			if err != nil {
+				continue
+			}
+			if inodes[link] {
+				return fmt.Sprintf("%s (pid %d)", processName(pid), pid)
+			}
+		}
+	}
+	return "an unknown process"
+}
+
+// listeningInodes returns the "socket:[inode]" links of sockets listening on port.
+func listeningInodes(port int) map[string]bool {
+	inodes := map[string]bool{}
+	hexPort := fmt.Sprintf("%04X", port)
+	for _, filename := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		file, err := os.Open(filename)
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(file)
+		scanner.Scan() // skip the header
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) < 10 || fields[3] != tcpListenState {
+				continue
+			}
+			if !strings.HasSuffix(fields[1], ":"+hexPort) {
+				continue
+			}
+			inodes[fmt.Sprintf("socket:[%s]", fields[9])] = true
+		}
+		file.Close()
+	}
+	return inodes
+}
+
+func processName(pid int) string {
+	comm, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(comm))
+}
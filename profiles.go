@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/elbow-jason/dgraph_helper/prompt"
+)
+
+const noProfile = "none"
+
+// profilesDir holds user profiles, answers files named <profile>.yaml.
+var profilesDir = path.Join(helperConfigDir, "profiles")
+
+type profile struct {
+	description string
+	answers     map[string]interface{} // keyed by flag name, like an answers file
+	// capMemory sizes memory_mb and a systemd MemoryMax for this host.
+	capMemory bool
+}
+
+// builtinProfiles pre-populate the answers for common deployment shapes.
+// A user profile with the same name replaces the built-in one. Hardening
+// that depends on the clients, such as TLS or nomutations, is left to the
+// prompts: no profile can know the certificates or whether clients write.
+var builtinProfiles = map[string]profile{
+	"dev-single": {
+		description: "one host for development: localhost only, verbose, every query traced",
+		answers: map[string]interface{}{
+			"bindall":      false,
+			"debugmode":    true,
+			"trace":        1.0,
+			"expose_trace": true,
+			"memory_mb":    1025,
+			"MemoryMax":    0,
+		},
+	},
+	"prod-single": {
+		description: "one production host: localhost only, quiet, memory sized and capped for this host, daily exports, logs to files",
+		answers: map[string]interface{}{
+			"bindall":          false,
+			"debugmode":        false,
+			"trace":            0.01,
+			"expose_trace":     false,
+			"schedule_exports": true,
+			"export_schedule":  "daily",
+			"export_retention": 7,
+			"logs":             logToFiles,
+		},
+		capMemory: true,
+	},
+	"prod-cluster-node": {
+		description: "one server of a production cluster: listens on all interfaces, quiet, memory sized and capped for this host, daily exports, logs to files",
+		answers: map[string]interface{}{
+			"bindall":          true,
+			"debugmode":        false,
+			"trace":            0.01,
+			"expose_trace":     false,
+			"schedule_exports": true,
+			"export_schedule":  "daily",
+			"export_retention": 7,
+			"logs":             logToFiles,
+		},
+		capMemory: true,
+	},
+}
+
+// profileNames lists the built-in and user profiles.
+func profileNames() []string {
+	names := []string{}
+	for name := range builtinProfiles {
+		names = append(names, name)
+	}
+	files, _ := ioutil.ReadDir(profilesDir)
+	for _, file := range files {
+		name := strings.TrimSuffix(file.Name(), ".yaml")
+		if name != file.Name() && !containsString(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// chooseProfile asks which profile to start from.
+func chooseProfile() string {
+	names := profileNames()
+	help := []string{"A profile pre-fills the answers; every question is still asked."}
+	for _, name := range names {
+		description := "user profile in " + profilesDir
+		if builtin, ok := builtinProfiles[name]; ok {
+			description = builtin.description
+		}
+		help = append(help, fmt.Sprintf("%s: %s", name, description))
+	}
+	return prompt.SelectString("Start from a profile?", strings.Join(help, "\n"), append([]string{noProfile}, names...), noProfile)
+}
+
+// readProfile adds the answers of the profile name to fieldOverrides,
+// below the flags and the answers file.
+func readProfile(name string) error {
+	if name == noProfile {
+		return nil
+	}
+	err := readAnswers(path.Join(profilesDir, name+".yaml"), true)
+	if !os.IsNotExist(err) {
+		return err
+	}
+	builtin, ok := builtinProfiles[name]
+	if !ok {
+		return fmt.Errorf("Unknown profile %s (expected one of %s)", name, strings.Join(profileNames(), ", "))
+	}
+	if builtin.capMemory {
+		if err := addAnswers("profile "+name, hostMemoryAnswers(), true); err != nil {
+			return err
+		}
+	}
+	return addAnswers("profile "+name, builtin.answers, true)
+}
+
+// hostMemoryAnswers proposes memory_mb for this host and a MemoryMax above
+// it. A memory_mb given before, e.g. as a flag, is used for MemoryMax.
+func hostMemoryAnswers() map[string]interface{} {
+	cfg := defaultConfig()
+	cfg.setRecommendedMemoryMb()
+	cfg.applyOverrides()
+	return map[string]interface{}{
+		"memory_mb": cfg.MemoryMb,
+		"MemoryMax": cfg.defaultMemoryMax(),
+	}
+}
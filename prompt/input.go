@@ -2,19 +2,29 @@ package prompt
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/AlecAivazis/survey"
 )
 
 // InputFloat64 .
-func InputFloat64(message string, defaultNum float64, validator survey.Validator) float64 {
+func InputFloat64(message string, help string, defaultNum float64, validator survey.Validator) float64 {
 	stringNum := fmt.Sprintf("%.2f", defaultNum)
+	if !Interactive {
+		answer := lineAnswer(message, strconv.FormatFloat(defaultNum, 'f', -1, 64), validator)
+		num, err := strconv.ParseFloat(answer, 64)
+		if err != nil {
+			panic(err)
+		}
+		return num
+	}
 	for {
 		theSurvey := []*survey.Question{
 			{
 				Name: "num",
 				Prompt: &survey.Input{
 					Message: message,
+					Help:    help,
 					Default: stringNum,
 				},
 				Validate: validator,
@@ -34,13 +44,16 @@ func InputFloat64(message string, defaultNum float64, validator survey.Validator
 }
 
 // InputString .
-func InputString(message string, defaultAnswer string, validator survey.Validator) string {
+func InputString(message string, help string, defaultAnswer string, validator survey.Validator) string {
+	if !Interactive {
+		return lineAnswer(message, defaultAnswer, validator)
+	}
 	var questions []*survey.Question
 	if defaultAnswer == "" {
 		questions = []*survey.Question{
 			{
 				Name:     "value",
-				Prompt:   &survey.Input{Message: message},
+				Prompt:   &survey.Input{Message: message, Help: help},
 				Validate: validator,
 			},
 		}
@@ -48,7 +61,7 @@ func InputString(message string, defaultAnswer string, validator survey.Validato
 		questions = []*survey.Question{
 			{
 				Name:     "value",
-				Prompt:   &survey.Input{Message: message, Default: defaultAnswer},
+				Prompt:   &survey.Input{Message: message, Help: help, Default: defaultAnswer},
 				Validate: validator,
 			},
 		}
@@ -56,12 +69,50 @@ func InputString(message string, defaultAnswer string, validator survey.Validato
 	answers := struct {
 		Value string `survey:"value"`
 	}{}
-	survey.Ask(questions, &answers)
+	err := survey.Ask(questions, &answers)
+	if err != nil {
+		panic(err)
+	}
 	return answers.Value
 }
 
+// InputPassword asks for a secret without echoing it. Leaving it empty keeps currentAnswer.
+func InputPassword(message string, help string, currentAnswer string) string {
+	answer := ""
+	if Interactive {
+		prompt := &survey.Password{Message: message, Help: help}
+		err := survey.AskOne(prompt, &answer, nil)
+		if err != nil {
+			panic(err)
+		}
+	} else {
+		// not echoed; an empty line keeps currentAnswer
+		answer, _ = readLine(message, "")
+		fmt.Println()
+	}
+	if answer == "" {
+		return currentAnswer
+	}
+	return answer
+}
+
 // InputInteger asks a question. has a default value (or not). returns an int.
-func InputInteger(message string, defaultNum int, hasDefault bool, validator survey.Validator) int {
+func InputInteger(message string, help string, defaultNum int, hasDefault bool, validator survey.Validator) int {
+	if !Interactive {
+		defaultAnswer := ""
+		if hasDefault {
+			defaultAnswer = strconv.Itoa(defaultNum)
+		}
+		answer := lineAnswer(message, defaultAnswer, validator)
+		if answer == "" {
+			return defaultNum
+		}
+		num, err := strconv.Atoi(answer)
+		if err != nil {
+			panic(err)
+		}
+		return num
+	}
 	var theSurvey []*survey.Question
 	if hasDefault {
 		stringNum := fmt.Sprintf("%d", defaultNum)
@@ -70,6 +121,7 @@ func InputInteger(message string, defaultNum int, hasDefault bool, validator sur
 				Name: "num",
 				Prompt: &survey.Input{
 					Message: message,
+					Help:    help,
 					Default: stringNum,
 				},
 				Validate: validator,
@@ -81,6 +133,7 @@ func InputInteger(message string, defaultNum int, hasDefault bool, validator sur
 				Name: "num",
 				Prompt: &survey.Input{
 					Message: message,
+					Help:    help,
 				},
 				Validate: validator,
 			},
@@ -100,14 +153,21 @@ func InputInteger(message string, defaultNum int, hasDefault bool, validator sur
 }
 
 // InputYesOrNo asks a yes or no question with a default answer and returns a bool
-func InputYesOrNo(message string, defaultAnswer bool) bool {
+func InputYesOrNo(message string, help string, defaultAnswer bool) bool {
+	if !Interactive {
+		return boolYesOrNo(lineOption(message, optionsYesOrNo(defaultAnswer), stringYesOrNo(defaultAnswer)))
+	}
 	userAnswer := ""
 	prompt := &survey.Select{
 		Message: message,
+		Help:    help,
 		Options: optionsYesOrNo(defaultAnswer),
 		Default: stringYesOrNo(defaultAnswer),
 	}
-	survey.AskOne(prompt, &userAnswer, nil)
+	err := survey.AskOne(prompt, &userAnswer, nil)
+	if err != nil {
+		panic(err)
+	}
 	return boolYesOrNo(userAnswer)
 }
 
@@ -132,22 +192,73 @@ func optionsYesOrNo(defaultAnswer bool) []string {
 	return []string{"N", "y"}
 }
 
-// MultiSelectInts .
-func MultiSelectInts(message string, start int, count int) []string {
+// MultiSelectInts asks the user to pick at least one of the ints from 0 to count-1
+func MultiSelectInts(message string, help string, start int, count int, defaultAnswers []string) []string {
 	chosenNumStrings := []string{}
 	numStrings := make([]string, count)
 	for i := 0; i < count; i++ {
 		numStrings[i] = fmt.Sprintf("%d", i)
 	}
+	if !Interactive {
+		return lineOptions(message, numStrings, defaultAnswers)
+	}
 	prompt := &survey.MultiSelect{
 		Message:  message,
+		Help:     help,
 		Options:  numStrings,
+		Default:  defaultAnswers,
 		PageSize: count,
 	}
 	for {
-		survey.AskOne(prompt, &chosenNumStrings, nil)
+		err := survey.AskOne(prompt, &chosenNumStrings, nil)
+		if err != nil {
+			panic(err)
+		}
 		if len(chosenNumStrings) > 0 {
 			return chosenNumStrings
 		}
 	}
 }
+
+// SelectString asks the user to pick one of the options and returns the chosen option
+func SelectString(message string, help string, options []string, defaultAnswer string) string {
+	if !Interactive {
+		return lineOption(message, options, defaultAnswer)
+	}
+	userAnswer := ""
+	prompt := &survey.Select{
+		Message: message,
+		Help:    help,
+		Options: options,
+		Default: defaultAnswer,
+	}
+	err := survey.AskOne(prompt, &userAnswer, nil)
+	if err != nil {
+		panic(err)
+	}
+	return userAnswer
+}
+
+// MultiSelectStrings asks the user to pick at least one of the options
+func MultiSelectStrings(message string, help string, options []string, defaultAnswers []string) []string {
+	if !Interactive {
+		return lineOptions(message, options, defaultAnswers)
+	}
+	chosen := []string{}
+	prompt := &survey.MultiSelect{
+		Message:  message,
+		Help:     help,
+		Options:  options,
+		Default:  defaultAnswers,
+		PageSize: len(options),
+	}
+	for {
+		err := survey.AskOne(prompt, &chosen, nil)
+		if err != nil {
+			panic(err)
+		}
+		if len(chosen) > 0 {
+			return chosen
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/AlecAivazis/survey"
+	"golang.org/x/sys/unix"
+)
+
+// Interactive is false when stdin is not a terminal, e.g. a pipe, a file
+// or a CI job. The prompts then print each question and read its answer
+// from the next line of stdin instead of showing a menu.
+var Interactive = isTerminal(os.Stdin)
+
+// isTerminal reads the terminal attributes of file, which fails for
+// anything but a terminal, /dev/null included.
+func isTerminal(file *os.File) bool {
+	_, err := unix.IoctlGetTermios(int(file.Fd()), ioctlReadTermios)
+	return err == nil
+}
+
+var stdinLines = bufio.NewReader(os.Stdin)
+
+// readLine prints the first line of message and reads one line of stdin.
+// An empty line or the end of stdin takes defaultAnswer; given is false then.
+func readLine(message string, defaultAnswer string) (answer string, given bool) {
+	question := strings.SplitN(message, "\n", 2)[0]
+	if defaultAnswer == "" {
+		fmt.Printf("? %s ", question)
+	} else {
+		fmt.Printf("? %s [%s] ", question, defaultAnswer)
+	}
+	line, err := stdinLines.ReadString('\n')
+	if err != nil && err != io.EOF {
+		panic(err)
+	}
+	answer = strings.TrimSpace(line)
+	if answer == "" {
+		return defaultAnswer, false
+	}
+	return answer, true
+}
+
+// lineAnswer reads the answer to message. A given answer that fails the
+// validator exits, since it cannot be asked again. The default is not
+// validated: the caller reports the settings left without a valid value.
+func lineAnswer(message string, defaultAnswer string, validator survey.Validator) string {
+	answer, given := readLine(message, defaultAnswer)
+	fmt.Println(answer)
+	if given && validator != nil {
+		if err := validator(answer); err != nil {
+			log.Fatalf("%s: %q: %v", message, answer, err)
+		}
+	}
+	return answer
+}
+
+// lineOption reads one of options, given as its text or its number from 1.
+func lineOption(message string, options []string, defaultAnswer string) string {
+	answer, given := readLine(fmt.Sprintf("%s (%s)", message, strings.Join(options, ", ")), defaultAnswer)
+	fmt.Println(answer)
+	if !given {
+		return defaultAnswer
+	}
+	option, err := findOption(options, answer)
+	if err != nil {
+		log.Fatalf("%s: %v", message, err)
+	}
+	return option
+}
+
+// lineOptions reads a comma separated list of options, given as their text
+// or their numbers from 1.
+func lineOptions(message string, options []string, defaultAnswers []string) []string {
+	answer, given := readLine(fmt.Sprintf("%s (%s)", message, strings.Join(options, ", ")), strings.Join(defaultAnswers, ","))
+	fmt.Println(answer)
+	if !given {
+		return defaultAnswers
+	}
+	chosen := []string{}
+	for _, text := range strings.Split(answer, ",") {
+		option, err := findOption(options, strings.TrimSpace(text))
+		if err != nil {
+			log.Fatalf("%s: %v", message, err)
+		}
+		chosen = append(chosen, option)
+	}
+	return chosen
+}
+
+// numericOptions is true when an option is a number itself, e.g. a group.
+// Numbers then only match the option's text, never its position.
+func numericOptions(options []string) bool {
+	for _, option := range options {
+		if _, err := strconv.Atoi(option); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func findOption(options []string, answer string) (string, error) {
+	for _, option := range options {
+		if strings.EqualFold(option, answer) {
+			return option, nil
+		}
+	}
+	if i, err := strconv.Atoi(answer); err == nil && i >= 1 && i <= len(options) && !numericOptions(options) {
+		return options[i-1], nil
+	}
+	return "", fmt.Errorf("%q is not one of %s", answer, strings.Join(options, ", "))
+}
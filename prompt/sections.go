@@ -0,0 +1,112 @@
+package prompt
+
+import (
+	"github.com/AlecAivazis/survey"
+)
+
+const (
+	sectionYes  = "y"
+	sectionNo   = "n"
+	sectionBack = "<< back to the previous section"
+	sectionDone = ">> skip the remaining sections"
+)
+
+const sectionHelp = "y asks this section's questions, n keeps the current values, back returns to the previous section and skip keeps the current values of every remaining section."
+
+// Section is a group of questions that RunSections can skip or return to.
+type Section struct {
+	// Question asks whether to enter the section, e.g. "Change dgraph's ports config?".
+	// Sections without a Question always run and cannot be gone back to.
+	Question string
+	// Default is the answer to Question when the user just presses enter.
+	Default bool
+	// Applies reports whether the section is relevant; nil means it always is.
+	Applies func() bool
+	// Ask runs the section's questions.
+	Ask func()
+	// Skipped, if set, runs when the user answers no to Question.
+	Skipped func()
+}
+
+func (section Section) applies() bool {
+	return section.Applies == nil || section.Applies()
+}
+
+func (section Section) skip() {
+	if section.Skipped != nil {
+		section.Skipped()
+	}
+}
+
+// RunSections asks the sections in order. Each Question is a menu that
+// enters the section, skips it, goes back to the previous section with a
+// Question, or skips every remaining section with a Question.
+func RunSections(sections []Section) {
+	skipRest := false
+	for i := 0; i < len(sections); i++ {
+		section := sections[i]
+		if !section.applies() {
+			continue
+		}
+		if section.Question == "" {
+			section.Ask()
+			continue
+		}
+		if skipRest {
+			section.skip()
+			continue
+		}
+		previous := previousSection(sections, i)
+		switch askSection(section, previous >= 0) {
+		case sectionYes:
+			section.Ask()
+		case sectionNo:
+			section.skip()
+		case sectionBack:
+			// the loop increments i, landing on the previous section
+			i = previous - 1
+		case sectionDone:
+			skipRest = true
+			section.skip()
+		}
+	}
+}
+
+// previousSection returns the index of the last applicable section with a
+// Question before i, or -1.
+func previousSection(sections []Section, i int) int {
+	for j := i - 1; j >= 0; j-- {
+		if sections[j].Question != "" && sections[j].applies() {
+			return j
+		}
+	}
+	return -1
+}
+
+func askSection(section Section, canGoBack bool) string {
+	options := []string{sectionNo, sectionYes}
+	defaultAnswer := sectionNo
+	if section.Default {
+		options = []string{sectionYes, sectionNo}
+		defaultAnswer = sectionYes
+	}
+	if canGoBack {
+		options = append(options, sectionBack)
+	}
+	options = append(options, sectionDone)
+	if !Interactive {
+		return lineOption(section.Question, options, defaultAnswer)
+	}
+	answer := ""
+	prompt := &survey.Select{
+		Message: section.Question,
+		Help:    sectionHelp,
+		Options: options,
+		Default: defaultAnswer,
+	}
+	err := survey.AskOne(prompt, &answer, nil)
+	if err != nil {
+		panic(err)
+	}
+	return answer
+}
@@ -3,11 +3,18 @@ package prompt
 import (
 	"fmt"
 	"net"
+	"os"
+	"os/exec"
 	"regexp"
 	"strconv"
 )
 
 var groupsRegex = regexp.MustCompile("^(\\d+(,\\d+)?(-\\d+)?)+$")
+var hostnameRegex = regexp.MustCompile("^([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)(\\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*\\.?$")
+
+// numericTopLabelRegex matches names whose last label is all digits, like
+// 300.1.1.1 or 1.2.3. Those are mistyped IPv4 addresses, not hostnames.
+var numericTopLabelRegex = regexp.MustCompile("(^|\\.)[0-9]+\\.?$")
 
 // ZeroToOneOnly .
 func ZeroToOneOnly(answer interface{}) error {
@@ -65,6 +72,33 @@ func IPv4Validator(ip interface{}) error {
 	return nil
 }
 
+// HostValidator ensures an input is an IPv4 address, an IPv6 address or a DNS name
+func HostValidator(host interface{}) error {
+	hostString := host.(string)
+	if net.ParseIP(hostString) != nil {
+		return nil
+	}
+	if len(hostString) > 253 || !hostnameRegex.MatchString(hostString) || numericTopLabelRegex.MatchString(hostString) {
+		return fmt.Errorf("Invalid IP address or hostname. Got %v", hostString)
+	}
+	return nil
+}
+
+// ResolvableHostValidator ensures an input is an IP address or a DNS name that resolves
+func ResolvableHostValidator(host interface{}) error {
+	if err := HostValidator(host); err != nil {
+		return err
+	}
+	hostString := host.(string)
+	if net.ParseIP(hostString) != nil {
+		return nil
+	}
+	if _, err := net.LookupHost(hostString); err != nil {
+		return fmt.Errorf("Could not resolve %s: %v", hostString, err)
+	}
+	return nil
+}
+
 // PortValidator ensures an input is an int between 0 and 65535
 // (it could return uint16, but it does not)
 func PortValidator(answer interface{}) error {
@@ -91,6 +125,40 @@ func IntValidator(answer interface{}) error {
 	return nil
 }
 
+// FileExistsValidator ensures an input is the path of an existing regular file
+func FileExistsValidator(answer interface{}) error {
+	answerStr := answer.(string)
+	info, err := os.Stat(answerStr)
+	if err != nil {
+		return fmt.Errorf("Could not find file %s", answerStr)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("Expected a file but %s is a directory", answerStr)
+	}
+	return nil
+}
+
+// OptionalFileExistsValidator is FileExistsValidator but also accepts an empty answer
+func OptionalFileExistsValidator(answer interface{}) error {
+	if answer.(string) == "" {
+		return nil
+	}
+	return FileExistsValidator(answer)
+}
+
+// CalendarValidator ensures an input is a systemd OnCalendar expression (uses systemd-analyze)
+func CalendarValidator(answer interface{}) error {
+	answerStr := answer.(string)
+	if answerStr == "" {
+		return fmt.Errorf("A calendar expression is required")
+	}
+	output, err := exec.Command("systemd-analyze", "calendar", answerStr).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("Invalid calendar expression %s: %s", answerStr, output)
+	}
+	return nil
+}
+
 // AlwaysValid returns nil as error always
 func AlwaysValid(_answer interface{}) error {
 	return nil
@@ -118,4 +186,17 @@ func PositiveIntValidator(answer interface{}) error {
 	return nil
 }
 
+// NonNegativeIntValidator .
+func NonNegativeIntValidator(answer interface{}) error {
+	answerStr := answer.(string)
+	num, err := strconv.Atoi(answerStr)
+	if err != nil {
+		return fmt.Errorf("Invalid Integer. Got %s", answerStr)
+	}
+	if num < 0 {
+		return fmt.Errorf("Must not be negative. Got %s", answerStr)
+	}
+	return nil
+}
+
 // GroupsNumbers returns the numbers of a group
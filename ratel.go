@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+const ratelBinary = "/usr/local/bin/dgraph-ratel"
+
+const ratelServiceName = "dgraph-ratel"
+
+// ratelAddr is the HTTP address of the data server that Ratel talks to.
+func (cfg *allConfig) ratelAddr() string {
+	scheme := "http"
+	if cfg.TlsOn {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://localhost:%d", scheme, cfg.Port+cfg.PortOffset)
+}
+
+func (cfg *allConfig) startRatelCommand() string {
+	return fmt.Sprintf("%s -port %d -addr %s", ratelBinary, cfg.RatelPort, cfg.ratelAddr())
+}
+
+// ratelSystemDUnit starts Ratel after the data server on the same host.
+func (cfg *allConfig) ratelSystemDUnit() string {
+	dependencies := []string{}
+	if cfg.RunAlpha {
+		dependencies = append(dependencies, cfg.serviceName()+".service")
+	}
+	return systemDUnit("Dgraph Ratel UI", cfg.startRatelCommand(), dependencies, cfg.logDirectives(ratelServiceName))
+}
+
+func (cfg *allConfig) writeRatelSystemDUnit() {
+	if _, err := os.Stat(ratelBinary); err != nil {
+		fmt.Printf("%s was not installed by this dgraph release. The Ratel UI will not start.\n", ratelBinary)
+	}
+	err := ioutil.WriteFile(unitFilepath(ratelServiceName), []byte(cfg.ratelSystemDUnit()), os.ModePerm)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (cfg *allConfig) ratelHealthURL() string {
+	return fmt.Sprintf("http://localhost:%d/", cfg.RatelPort)
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// Relocate moves the install directory, P or W of an existing install,
+// updates config.yaml and the units to match and restarts dgraph.
+func Relocate(args []string) {
+	flags := flag.NewFlagSet("relocate", flag.ExitOnError)
+	configPath := flags.String("config", path.Join(defaultConfig().installDir, defaultConfig().yamlFilename), "config.yaml of the dgraph to relocate")
+	newInstallDir := flags.String("dir", "", "new install directory (moves everything in it)")
+	newP := flags.String("p", "", "new directory for posting lists")
+	newW := flags.String("w", "", "new directory for write-ahead logs")
+	flags.Parse(args)
+	if *newInstallDir == "" && *newP == "" && *newW == "" {
+		log.Fatal("Nothing to relocate. Pass at least one of -dir, -p or -w.")
+	}
+	if err := ensurePermissions(); err != nil {
+		log.Fatal(err)
+	}
+	cfg, err := readConfigDotYaml(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	moves := [][2]string{}
+	oldInstallDir := cfg.installDir
+	if *newInstallDir != "" {
+		moves = append(moves, [2]string{cfg.installDir, path.Clean(*newInstallDir)})
+		cfg.rebase(cfg.installDir, path.Clean(*newInstallDir))
+	}
+	if *newP != "" {
+		moves = append(moves, [2]string{cfg.P, path.Clean(*newP)})
+		cfg.P = path.Clean(*newP)
+	}
+	if *newW != "" {
+		moves = append(moves, [2]string{cfg.W, path.Clean(*newW)})
+		cfg.W = path.Clean(*newW)
+	}
+	for _, move := range moves {
+		if _, err := os.Stat(move[1]); err == nil {
+			log.Fatalf("%s already exists. Relocate only moves to new paths.", move[1])
+		}
+	}
+
+	units := runningUnits()
+	for _, unit := range units {
+		stopDgraphService(unit)
+	}
+	for _, move := range moves {
+		fmt.Printf("Moving %s to %s\n", move[0], move[1])
+		if err := moveDir(move[0], move[1]); err != nil {
+			log.Fatal(err)
+		}
+	}
+	cfg.writeConfigDotYaml()
+	if *newInstallDir != "" {
+		// zero.yaml and the units refer to files under the install directory
+		filenames := []string{cfg.zeroDotYamlFilepath(), logrotateFilepath}
+		for _, unit := range installedUnits() {
+			filenames = append(filenames, path.Join(systemDpath, unit))
+		}
+		for _, filename := range filenames {
+			if err := rewritePaths(filename, oldInstallDir, cfg.installDir); err != nil {
+				log.Fatal(err)
+			}
+		}
+		reloadDaemons()
+	}
+	for i := len(units) - 1; i >= 0; i-- {
+		startDgraphService(units[i])
+	}
+	// only the units that were running are restarted and health checked
+	cfg.RunAlpha = containsString(units, cfg.serviceName()+".service")
+	cfg.RunZero = containsString(units, zeroServiceName+".service")
+	cfg.RunRatel = containsString(units, ratelServiceName+".service")
+	if err := cfg.checkHealth(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Relocated. config.yaml is now %s\n", cfg.configDotYamlFilepath())
+}
+
+// runningUnits are the installed units that are active, in the order they
+// are stopped: timers first, zero last. Units the operator stopped stay
+// stopped. The oneshot export service is left out so restarting does not
+// trigger an export.
+func runningUnits() []string {
+	units := []string{}
+	for _, unit := range installedUnits() {
+		if unit != exportServiceName+".service" && getUnitStatus(unit).ActiveState == "active" {
+			units = append(units, unit)
+		}
+	}
+	return units
+}
+
+// rebase moves every path of the config that is under oldDir to newDir.
+func (cfg *allConfig) rebase(oldDir string, newDir string) {
+	for _, field := range []*string{&cfg.P, &cfg.W, &cfg.Export, &cfg.ZeroW, &cfg.TlsCert, &cfg.TlsCertKey, &cfg.TlsCaCerts} {
+		if *field == oldDir || strings.HasPrefix(*field, oldDir+"/") {
+			*field = newDir + strings.TrimPrefix(*field, oldDir)
+		}
+	}
+	cfg.installDir = newDir
+}
+
+// rewritePaths replaces oldDir/ with newDir/ in a text file, if it exists.
+func rewritePaths(filename string, oldDir string, newDir string) error {
+	text, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	rewritten := strings.Replace(string(text), oldDir+"/", newDir+"/", -1)
+	return ioutil.WriteFile(filename, []byte(rewritten), info.Mode())
+}
+
+// moveDir renames from to to. Across filesystems it copies the tree,
+// verifies every file of the copy and only then removes from.
+func moveDir(from string, to string) error {
+	if err := os.MkdirAll(path.Dir(to), os.ModePerm); err != nil {
+		return err
+	}
+	err := os.Rename(from, to)
+	if err == nil {
+		return nil
+	}
+	if linkErr, ok := err.(*os.LinkError); !ok || linkErr.Err != syscall.EXDEV {
+		return err
+	}
+	if err := copyTree(from, to); err != nil {
+		return err
+	}
+	if err := verifyTree(from, to); err != nil {
+		return fmt.Errorf("The copy in %s does not match %s (%v). %s was not removed.", to, from, err, from)
+	}
+	return os.RemoveAll(from)
+}
+
+func copyTree(from string, to string) error {
+	return filepath.Walk(from, func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, filename)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(to, rel)
+		switch {
+		case info.IsDir():
+			err = os.MkdirAll(dest, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			var link string
+			if link, err = os.Readlink(filename); err == nil {
+				err = os.Symlink(link, dest)
+			}
+		case info.Mode().IsRegular():
+			err = copyFile(filename, dest, info.Mode().Perm())
+		default:
+			err = fmt.Errorf("Cannot copy %s (not a file, directory or symlink)", filename)
+		}
+		if err != nil {
+			return err
+		}
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+			return os.Lchown(dest, int(stat.Uid), int(stat.Gid))
+		}
+		return nil
+	})
+}
+
+func copyFile(from string, to string, perm os.FileMode) error {
+	source, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	dest, err := os.OpenFile(to, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dest, source); err != nil {
+		dest.Close()
+		return err
+	}
+	if err := dest.Sync(); err != nil {
+		dest.Close()
+		return err
+	}
+	return dest.Close()
+}
+
+// verifyTree checks that every regular file under from has an identical copy under to.
+func verifyTree(from string, to string) error {
+	return filepath.Walk(from, func(filename string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(from, filename)
+		if err != nil {
+			return err
+		}
+		want, err := fileChecksum(filename)
+		if err != nil {
+			return err
+		}
+		got, err := fileChecksum(filepath.Join(to, rel))
+		if err != nil {
+			return err
+		}
+		if got != want {
+			return fmt.Errorf("checksum mismatch for %s", rel)
+		}
+		return nil
+	})
+}
+
+func fileChecksum(filename string) (string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/elbow-jason/dgraph_helper/prompt"
+)
+
+const (
+	reviewProceed = "Proceed with install"
+	reviewEdit    = "Edit a setting"
+	reviewAbort   = "Abort"
+	reviewBack    = "<< back to the summary"
+)
+
+// rowEditors maps the keys of summaryRows, with the release's names for
+// renamed keys, to the prompt that changes the row.
+func (cfg *allConfig) rowEditors() map[string]func() {
+	editors := map[string]func(){}
+	for _, field := range configFields {
+		if field.change == nil && field.question == "" {
+			continue
+		}
+		field := field
+		editors[cfg.schema().name(field.key)] = func() { cfg.changeField(field) }
+	}
+	return editors
+}
+
+// reviewConfig prints the summary and lets the user edit any row of it
+// until they proceed with the install (true) or abort (false).
+func (cfg *allConfig) reviewConfig() bool {
+	for {
+		if err := cfg.printConfigSummary(*outputFormat); err != nil {
+			log.Fatal(err)
+		}
+		switch prompt.SelectString("Review the configuration", "Edit a setting asks the question of one summary row again. Abort exits without writing anything.", []string{reviewProceed, reviewEdit, reviewAbort}, reviewProceed) {
+		case reviewProceed:
+			return true
+		case reviewAbort:
+			return false
+		}
+		cfg.editRow()
+		cfg.ensurePorts()
+	}
+}
+
+// editRow asks which row of the summary to change and runs its prompt.
+func (cfg *allConfig) editRow() {
+	editors := cfg.rowEditors()
+	options := []string{}
+	optionEditors := map[string]func(){}
+	for _, row := range cfg.summaryRows() {
+		editor, ok := editors[row[0]]
+		if !ok {
+			continue
+		}
+		option := fmt.Sprintf("%s = %s", row[0], row[1])
+		options = append(options, option)
+		optionEditors[option] = editor
+	}
+	options = append(options, reviewBack)
+	choice := prompt.SelectString("Which setting do you want to change?", "Only the rows that have a question are listed.", options, options[0])
+	if editor, ok := optionEditors[choice]; ok {
+		editor()
+	}
+}
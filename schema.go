@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// defaultDgraphVersion is the release the config keys of allConfig were written for.
+const defaultDgraphVersion = "v0.8"
+
+// versionCommentRegex finds the release a config.yaml was written for.
+var versionCommentRegex = regexp.MustCompile(`(?m)^# written by dgraph_helper for dgraph (\S+)$`)
+
+// flagSchema describes the flags one dgraph release accepts. Keys are the
+// v0.8 config.yaml keys used throughout allConfig (e.g. "memory_mb").
+type flagSchema struct {
+	version string
+	// command is the dgraph subcommand that runs a data server ("" for none).
+	command string
+	// installScriptURL and installArgs download and install the release.
+	installScriptURL string
+	installArgs      []string
+	// renamed maps keys to this release's name for them.
+	renamed map[string]string
+	// rejected keys are not prompted for and never written.
+	rejected map[string]bool
+	// tlsDir is set for releases that take a tls_dir instead of file paths.
+	tlsDir bool
+	// ratel is set for releases whose install script installs dgraph-ratel.
+	ratel bool
+	// peerDescription completes "The IP or hostname of ..." for the peer prompt.
+	peerDescription string
+	// peerPortMessage asks for the port of the peer.
+	peerPortMessage string
+	// peerRequired is set when every server must be given a peer.
+	peerRequired bool
+	// setDefaults changes the defaults of allConfig for this release.
+	setDefaults func(cfg *allConfig)
+}
+
+// v1Renamed holds the renames shared by the releases that run dgraph zero.
+var v1Renamed = map[string]string{
+	"p":         "postings",
+	"w":         "wal",
+	"memory_mb": "lru_mb",
+	"peer":      "zero",
+}
+
+// v1Rejected holds the keys dropped by the releases that run dgraph zero.
+// Ports are fixed and moved with port_offset; zero assigns groups.
+var v1Rejected = []string{
+	"port", "grpc_port", "workerport", "total groups", "groups", "gentlecommit", "pending",
+	"posting_tables", "sc", "ui", "group_conf", "cpu", "mem", "block", "dumpsg",
+}
+
+// setV1Defaults sets the fixed ports of the releases that run dgraph zero.
+func setV1Defaults(cfg *allConfig) {
+	cfg.Port = 8080
+	cfg.GrpcPort = 9080
+	cfg.Workerport = 7080
+	cfg.PeerPort = 5080
+}
+
+var flagSchemas = map[string]*flagSchema{
+	"v0.8": {
+		version:          "v0.8",
+		installScriptURL: "https://nightly.dgraph.io",
+		renamed:          map[string]string{},
+		rejected:         map[string]bool{},
+		peerDescription:  "a healthy peer in the cluster",
+		peerPortMessage:  "The workerport of the same peer",
+		setDefaults:      func(cfg *allConfig) {},
+	},
+	"v1.0": {
+		version:          "v1.0",
+		command:          "server",
+		installScriptURL: "https://get.dgraph.io",
+		installArgs:      []string{"-v=v1.0.11"},
+		renamed: mergeRenames(v1Renamed, map[string]string{
+			"tls.on":                  "tls_on",
+			"tls.cert":                "tls_cert",
+			"tls.cert_key":            "tls_cert_key",
+			"tls.cert_key_passphrase": "tls_cert_key_passphrase",
+			"tls.ca_certs":            "tls_ca_certs",
+			"tls.client_auth":         "tls_client_auth",
+			"tls.min_version":         "tls_min_version",
+			"tls.max_version":         "tls_max_version",
+			"tls.use_system_ca":       "tls_use_system_ca",
+		}),
+		rejected:        rejectedSet(v1Rejected),
+		ratel:           true,
+		peerDescription: "the dgraph zero to connect to",
+		peerPortMessage: "The grpc port of the same zero",
+		peerRequired:    true,
+		setDefaults:     setV1Defaults,
+	},
+	"v1.1": {
+		version:          "v1.1",
+		command:          "alpha",
+		installScriptURL: "https://get.dgraph.io",
+		installArgs:      []string{"-v=v1.1.1"},
+		renamed: mergeRenames(v1Renamed, map[string]string{
+			"tls.client_auth": "tls_client_auth",
+		}),
+		rejected: rejectedSet(append(v1Rejected,
+			"idx", "tls.on", "tls.cert", "tls.cert_key", "tls.cert_key_passphrase",
+			"tls.ca_certs", "tls.min_version", "tls.max_version", "tls.use_system_ca",
+		)),
+		tlsDir:          true,
+		ratel:           true,
+		peerDescription: "the dgraph zero to connect to",
+		peerPortMessage: "The grpc port of the same zero",
+		peerRequired:    true,
+		setDefaults:     setV1Defaults,
+	},
+}
+
+func mergeRenames(maps ...map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, m := range maps {
+		for key, name := range m {
+			merged[key] = name
+		}
+	}
+	return merged
+}
+
+func rejectedSet(keys []string) map[string]bool {
+	set := map[string]bool{}
+	for _, key := range keys {
+		set[key] = true
+	}
+	return set
+}
+
+// dgraphVersions returns the versions with a schema in ascending order.
+func dgraphVersions() []string {
+	versions := []string{}
+	for version := range flagSchemas {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+func schemaFor(version string) (*flagSchema, error) {
+	schema, ok := flagSchemas[version]
+	if !ok {
+		return nil, fmt.Errorf("Unsupported dgraph version %s (expected one of %v)", version, dgraphVersions())
+	}
+	return schema, nil
+}
+
+// supports returns false for keys the release does not accept.
+func (schema *flagSchema) supports(key string) bool {
+	return !schema.rejected[key]
+}
+
+// name returns the release's name for key.
+func (schema *flagSchema) name(key string) string {
+	if name, ok := schema.renamed[key]; ok {
+		return name
+	}
+	return key
+}
+
+// apply drops rejected keys from params and renames the rest.
+func (schema *flagSchema) apply(params map[string]interface{}) map[string]interface{} {
+	applied := map[string]interface{}{}
+	for key, value := range params {
+		if schema.supports(key) {
+			applied[schema.name(key)] = value
+		}
+	}
+	return applied
+}
+
+// unapply maps a config.yaml written for the release back to v0.8 keys.
+func (schema *flagSchema) unapply(params map[string]interface{}) map[string]interface{} {
+	keys := map[string]string{}
+	for key, name := range schema.renamed {
+		keys[name] = key
+	}
+	unapplied := map[string]interface{}{}
+	for name, value := range params {
+		if key, ok := keys[name]; ok {
+			unapplied[key] = value
+		} else {
+			unapplied[name] = value
+		}
+	}
+	return unapplied
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"github.com/elbow-jason/dgraph_helper/prompt"
+)
+
+// installSections is the prompt sequence of Install. Every section with a
+// Question can be skipped or gone back to (see prompt.RunSections); the
+// ones without a Question always run.
+func (cfg *allConfig) installSections() []prompt.Section {
+	return []prompt.Section{
+		{
+			Ask: cfg.changeDgraphVersion,
+		},
+		{
+			Applies: func() bool { return cfg.schema().hasZero() },
+			Ask:     cfg.changeRoles,
+		},
+		{
+			Question: "Change dgraph's base directory?",
+			Ask: func() {
+				oldDir := cfg.installDir
+				cfg.askField("install dir")
+				cfg.moveSubdirs(oldDir)
+			},
+		},
+		{
+			Question: "Change dgraph zero's config?",
+			Applies:  func() bool { return cfg.RunZero },
+			Ask:      func() { cfg.askFields(zeroSection) },
+		},
+		{
+			Question: "Change dgraph's subdirectories?",
+			Applies:  func() bool { return cfg.RunAlpha },
+			Ask:      func() { cfg.askFields(subdirectoriesSection) },
+		},
+		{
+			Question: "Change dgraph's ports config?",
+			Applies:  func() bool { return cfg.RunAlpha && cfg.schema().supports("port") },
+			Ask:      cfg.changePorts,
+		},
+		{
+			Question: "Change dgraph's engine config?",
+			Applies:  func() bool { return cfg.RunAlpha },
+			Ask:      func() { cfg.askFields(engineSection) },
+		},
+		{
+			Question: "Change dgraph's advanced engine config?",
+			Applies:  func() bool { return cfg.RunAlpha },
+			Ask:      cfg.changeAdvancedEngine,
+		},
+		{
+			Question: "Run the Ratel UI as a service?",
+			Default:  cfg.RunRatel,
+			Applies:  func() bool { return cfg.schema().ratel },
+			Ask: func() {
+				cfg.RunRatel = true
+				cfg.askFields(ratelSection)
+			},
+			Skipped: func() { cfg.RunRatel = false },
+		},
+		{
+			// offsets and the Ratel port are known from here on
+			Ask: cfg.ensurePorts,
+		},
+		{
+			Question: "Change dgraph's cluster config?",
+			Default:  cfg.Bindall,
+			Ask:      cfg.changeCluster,
+		},
+		{
+			Question: "Change dgraph's TLS config?",
+			Applies:  func() bool { return cfg.RunAlpha },
+			Ask:      cfg.changeTLS,
+		},
+		{
+			Question: "Schedule exports with a systemd timer?",
+			Default:  cfg.ScheduleExports,
+			Applies:  func() bool { return cfg.RunAlpha },
+			Ask: func() {
+				cfg.ScheduleExports = true
+				cfg.askFields(exportsSection)
+			},
+			Skipped: func() { cfg.ScheduleExports = false },
+		},
+		{
+			Ask: func() { cfg.askFields(logsSection) },
+		},
+	}
+}
+
+func (cfg *allConfig) changeCluster() {
+	cfg.Bindall = true
+	if cfg.RunAlpha {
+		cfg.askField("idx")
+		if cfg.schema().peerRequired || !cfg.isFirstServer() {
+			cfg.changePeer()
+		}
+		cfg.ask("groups", cfg.changeTotalGroups)
+	}
+	cfg.changeMyIP()
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// clockTicksPerSecond is USER_HZ, which is 100 on every Linux architecture dgraph runs on.
+const clockTicksPerSecond = 100
+
+type statusReport struct {
+	Services      []unitStatus `json:"services"`
+	DgraphVersion string       `json:"dgraph_version"`
+	Disk          []diskUsage  `json:"disk"`
+	Health        string       `json:"health"`
+}
+
+type unitStatus struct {
+	Unit          string  `json:"unit"`
+	ActiveState   string  `json:"active_state"`
+	SubState      string  `json:"sub_state"`
+	PID           int     `json:"pid"`
+	UptimeSeconds int64   `json:"uptime_seconds"`
+	MemoryMb      float64 `json:"memory_mb"`
+}
+
+type diskUsage struct {
+	Key    string  `json:"key"`
+	Path   string  `json:"path"`
+	UsedMb float64 `json:"used_mb"`
+	Error  string  `json:"error,omitempty"`
+}
+
+// Status prints the state of every installed dgraph unit together with
+// the disk usage of the data directories, the installed dgraph version
+// and the result of the data server's health check.
+func Status(args []string) {
+	flags := flag.NewFlagSet("status", flag.ExitOnError)
+	configPath := flags.String("config", path.Join(defaultConfig().installDir, defaultConfig().yamlFilename), "config.yaml of the dgraph to report on")
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	flags.Parse(args)
+
+	cfg, err := readConfigDotYaml(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	report := cfg.statusReport()
+	if *asJSON {
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(string(jsonBytes))
+		return
+	}
+	report.printTable()
+}
+
+func (cfg *allConfig) statusReport() statusReport {
+	report := statusReport{Services: []unitStatus{}, Disk: []diskUsage{}}
+	for _, unit := range installedUnits() {
+		report.Services = append(report.Services, getUnitStatus(unit))
+	}
+	version, err := dgraphVersion()
+	if err != nil {
+		version = fmt.Sprintf("unknown (%v)", err)
+	}
+	report.DgraphVersion = version
+	for _, dir := range [][2]string{{"p", cfg.P}, {"w", cfg.W}, {"export", cfg.Export}} {
+		usage := diskUsage{Key: dir[0], Path: dir[1]}
+		usedMb, err := dirUsageMb(dir[1])
+		if err != nil {
+			usage.Error = err.Error()
+		}
+		usage.UsedMb = usedMb
+		report.Disk = append(report.Disk, usage)
+	}
+	report.Health = "ok"
+	if err := waitForHealth(cfg.healthURL(), 2*time.Second); err != nil {
+		report.Health = err.Error()
+	}
+	return report
+}
+
+func getUnitStatus(unit string) unitStatus {
+	status := unitStatus{Unit: unit}
+	output, err := runCommandOutput("systemctl", "show", unit, "--property=ActiveState,SubState,MainPID")
+	if err != nil {
+		status.ActiveState = "unknown"
+		return status
+	}
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		switch parts[0] {
+		case "ActiveState":
+			status.ActiveState = parts[1]
+		case "SubState":
+			status.SubState = parts[1]
+		case "MainPID":
+			status.PID, _ = strconv.Atoi(parts[1])
+		}
+	}
+	if status.PID > 0 {
+		status.UptimeSeconds, _ = processUptimeSeconds(status.PID)
+		status.MemoryMb, _ = processMemoryMb(status.PID)
+	}
+	return status
+}
+
+// processUptimeSeconds compares the start time of pid (field 22 of
+// /proc/pid/stat, in clock ticks since boot) with /proc/uptime.
+func processUptimeSeconds(pid int) (int64, error) {
+	stat, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+	// the command name in field 2 may contain spaces, so count from its closing paren
+	fields := strings.Fields(string(stat[strings.LastIndex(string(stat), ")")+1:]))
+	if len(fields) < 20 {
+		return 0, fmt.Errorf("Unexpected /proc/%d/stat", pid)
+	}
+	startTicks, err := strconv.ParseInt(fields[19], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	uptime, err := ioutil.ReadFile("/proc/uptime")
+	if err != nil {
+		return 0, err
+	}
+	systemUptime, err := strconv.ParseFloat(strings.Fields(string(uptime))[0], 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(systemUptime) - startTicks/clockTicksPerSecond, nil
+}
+
+// processMemoryMb returns the resident memory (VmRSS) of pid.
+func processMemoryMb(pid int) (float64, error) {
+	file, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "VmRSS:" {
+			kb, err := strconv.ParseFloat(fields[1], 64)
+			return kb / 1024, err
+		}
+	}
+	return 0, fmt.Errorf("No VmRSS in /proc/%d/status", pid)
+}
+
+// dirUsageMb returns the disk space used by everything under dir, like du.
+func dirUsageMb(dir string) (float64, error) {
+	var bytes int64
+	err := filepath.Walk(dir, func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+			bytes += stat.Blocks * 512
+		}
+		return nil
+	})
+	return float64(bytes) / (1024 * 1024), err
+}
+
+func (report statusReport) printTable() {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Item", "Status", "Detail"})
+	for _, service := range report.Services {
+		detail := ""
+		if service.PID > 0 {
+			uptime := time.Duration(service.UptimeSeconds) * time.Second
+			detail = fmt.Sprintf("pid %d, up %s, %s MB", service.PID, uptime, float2string(service.MemoryMb))
+		}
+		table.Append([]string{service.Unit, fmt.Sprintf("%s (%s)", service.ActiveState, service.SubState), detail})
+	}
+	table.Append([]string{"dgraph version", report.DgraphVersion, dgraphBinary})
+	for _, usage := range report.Disk {
+		used := float2string(usage.UsedMb) + " MB"
+		if usage.Error != "" {
+			used = usage.Error
+		}
+		table.Append([]string{"disk " + usage.Key, used, usage.Path})
+	}
+	table.Append([]string{"health", report.Health, ""})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.Render()
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// summarySchemaVersion is bumped whenever a field of configSummary is
+// renamed or removed, so scripts reading the json can detect the change.
+const summarySchemaVersion = 1
+
+var summaryFormats = []string{"table", "json", "yaml", "markdown"}
+
+var summaryHeader = []string{"Key", "Value", "Description", "Destination"}
+
+type configSummary struct {
+	SchemaVersion int              `json:"schema_version" yaml:"schema_version"`
+	DgraphVersion string           `json:"dgraph_version" yaml:"dgraph_version"`
+	Settings      []summarySetting `json:"settings" yaml:"settings"`
+	Commands      summaryCommands  `json:"commands" yaml:"commands"`
+}
+
+type summarySetting struct {
+	Key         string `json:"key" yaml:"key"`
+	Value       string `json:"value" yaml:"value"`
+	Description string `json:"description" yaml:"description"`
+	Destination string `json:"destination" yaml:"destination"`
+}
+
+type summaryCommands struct {
+	Zero  string `json:"zero,omitempty" yaml:"zero,omitempty"`
+	Alpha string `json:"alpha,omitempty" yaml:"alpha,omitempty"`
+}
+
+// Show prints the configuration summary of an existing install.
+func Show(args []string) {
+	flags := flag.NewFlagSet("show", flag.ExitOnError)
+	configPath := flags.String("config", path.Join(defaultConfig().installDir, defaultConfig().yamlFilename), "config.yaml of the dgraph to show")
+	format := flags.String("output", *outputFormat, "format of the summary: table, json, yaml or markdown")
+	flags.Parse(args)
+
+	cfg, err := readConfigDotYaml(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.printConfigSummary(*format); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (cfg *allConfig) summary() configSummary {
+	summary := configSummary{
+		SchemaVersion: summarySchemaVersion,
+		DgraphVersion: cfg.DgraphVersion,
+		Settings:      []summarySetting{},
+	}
+	for _, row := range cfg.summaryRows() {
+		summary.Settings = append(summary.Settings, summarySetting{
+			Key:         row[0],
+			Value:       row[1],
+			Description: row[2],
+			Destination: row[3],
+		})
+	}
+	if cfg.RunZero {
+		summary.Commands.Zero = cfg.startZeroCommand()
+	}
+	if cfg.RunAlpha {
+		summary.Commands.Alpha = cfg.startDgraphCommand()
+	}
+	return summary
+}
+
+// printConfigSummary prints the summary in one of summaryFormats.
+func (cfg *allConfig) printConfigSummary(format string) error {
+	switch format {
+	case "table":
+		cfg.printConfigTable()
+	case "json":
+		jsonBytes, err := json.MarshalIndent(cfg.summary(), "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonBytes))
+	case "yaml":
+		yamlBytes, err := yaml.Marshal(cfg.summary())
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(yamlBytes))
+	case "markdown":
+		fmt.Print(cfg.summary().markdown())
+	default:
+		return fmt.Errorf("Unknown output format %q (use one of %s)", format, strings.Join(summaryFormats, ", "))
+	}
+	return nil
+}
+
+func (summary configSummary) markdown() string {
+	var md strings.Builder
+	fmt.Fprintf(&md, "| %s |\n", strings.Join(summaryHeader, " | "))
+	fmt.Fprintf(&md, "|%s\n", strings.Repeat(" --- |", len(summaryHeader)))
+	for _, setting := range summary.Settings {
+		cells := []string{setting.Key, setting.Value, setting.Description, setting.Destination}
+		for i, cell := range cells {
+			cells[i] = strings.Replace(cell, "|", `\|`, -1)
+		}
+		fmt.Fprintf(&md, "| %s |\n", strings.Join(cells, " | "))
+	}
+	if summary.Commands.Zero != "" {
+		fmt.Fprintf(&md, "\ndgraph %s zero command is `%s`\n", summary.DgraphVersion, summary.Commands.Zero)
+	}
+	if summary.Commands.Alpha != "" {
+		fmt.Fprintf(&md, "\ndgraph %s command is `%s`\n", summary.DgraphVersion, summary.Commands.Alpha)
+	}
+	return md.String()
+}
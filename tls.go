@@ -0,0 +1,145 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+
+	"github.com/elbow-jason/dgraph_helper/prompt"
+)
+
+// noClientAuth is the menu option for leaving tls.client_auth unset.
+const noClientAuth = "none"
+
+var tlsClientAuthOptions = []string{noClientAuth, "REQUEST", "REQUIREANY", "VERIFYIFGIVEN", "REQUIREANDVERIFY"}
+
+var tlsVersionOptions = []string{"TLS10", "TLS11", "TLS12"}
+
+func (cfg *allConfig) changeTLS() {
+	// releases with a tls_dir reject tls.on but still turn TLS on with it
+	cfg.askQuestion(fieldByKey("tls.on"))
+	if !cfg.TlsOn {
+		return
+	}
+	cfg.changeGenerateCerts()
+	if !cfg.GenerateCerts {
+		// releases with a tls_dir still need the key pair to find the directory
+		cfg.changeTlsKeyPair()
+		cfg.askField("tls.ca_certs")
+	}
+	cfg.ask("tls.client_auth", cfg.changeTlsClientAuth)
+	cfg.ask("tls.min_version", cfg.changeTlsVersions)
+	cfg.askField("tls.use_system_ca")
+}
+
+// changeGenerateCerts asks whether to generate a local CA and server
+// certificate (see Certs) during the install instead of using existing files.
+func (cfg *allConfig) changeGenerateCerts() {
+	cfg.GenerateCerts = prompt.InputYesOrNo("Generate a self-signed CA and server certificate?", fieldHelp("tls.generate"), cfg.GenerateCerts)
+	if cfg.GenerateCerts {
+		cfg.setGeneratedCertPaths()
+	}
+}
+
+// changeTlsKeyPair asks for the certificate, its key and the key's
+// passphrase until the key matches the certificate.
+func (cfg *allConfig) changeTlsKeyPair() {
+	for {
+		cfg.TlsCert = prompt.InputString("The certificate file?", fieldHelp("tls.cert"), cfg.TlsCert, prompt.FileExistsValidator)
+		cfg.TlsCertKey = prompt.InputString("The certificate key file?", fieldHelp("tls.cert_key"), cfg.TlsCertKey, prompt.FileExistsValidator)
+		cfg.TlsCertKeyPassphrase = prompt.InputPassword("The certificate key passphrase? (leave empty if the key is not encrypted)", fieldHelp("tls.cert_key_passphrase"), cfg.TlsCertKeyPassphrase)
+		err := verifyKeyPair(cfg.TlsCert, cfg.TlsCertKey, cfg.TlsCertKeyPassphrase)
+		if err == nil {
+			err = cfg.checkTlsDir()
+		}
+		if err == nil {
+			return
+		}
+		if !prompt.Interactive {
+			log.Fatalf("%v. Give a matching -tls.cert, -tls.cert_key and -tls.cert_key_passphrase.", err)
+		}
+		fmt.Println(err)
+	}
+}
+
+func (cfg *allConfig) changeTlsClientAuth() {
+	current := cfg.TlsClientAuth
+	if current == "" {
+		current = noClientAuth
+	}
+	choice := prompt.SelectString("TLS client authentication?", fieldHelp("tls.client_auth"), tlsClientAuthOptions, current)
+	if choice == noClientAuth {
+		choice = ""
+	}
+	cfg.TlsClientAuth = choice
+}
+
+func (cfg *allConfig) changeTlsVersions() {
+	for {
+		cfg.TlsMinVersion = prompt.SelectString("TLS min version?", fieldHelp("tls.min_version"), tlsVersionOptions, cfg.TlsMinVersion)
+		cfg.TlsMaxVersion = prompt.SelectString("TLS max version?", fieldHelp("tls.max_version"), tlsVersionOptions, cfg.TlsMaxVersion)
+		// the options are in ascending order and zero padded so they compare as strings
+		if cfg.TlsMinVersion <= cfg.TlsMaxVersion {
+			return
+		}
+		if !prompt.Interactive {
+			log.Fatalf("The min version (%s) cannot be higher than the max version (%s). Give other -tls.min_version or -tls.max_version.", cfg.TlsMinVersion, cfg.TlsMaxVersion)
+		}
+		fmt.Printf("The min version (%s) cannot be higher than the max version (%s).\n", cfg.TlsMinVersion, cfg.TlsMaxVersion)
+	}
+}
+
+// verifyKeyPair returns an error unless keyFile holds the private key of the
+// certificate in certFile. An encrypted key is decrypted with passphrase.
+func verifyKeyPair(certFile string, keyFile string, passphrase string) error {
+	certPEM, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return err
+	}
+	keyPEM, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return err
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return fmt.Errorf("No PEM data found in %s", keyFile)
+	}
+	if x509.IsEncryptedPEMBlock(block) {
+		der, err := x509.DecryptPEMBlock(block, []byte(passphrase))
+		if err != nil {
+			return fmt.Errorf("Could not decrypt %s: %v", keyFile, err)
+		}
+		keyPEM = pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: der})
+	}
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		return fmt.Errorf("The key in %s does not match the certificate in %s: %v", keyFile, certFile, err)
+	}
+	return nil
+}
+
+// checkTlsDir returns an error unless the key pair and the CA certificate
+// are in one directory under the names releases with a tls_dir read.
+func (cfg *allConfig) checkTlsDir() error {
+	if !cfg.schema().tlsDir {
+		return nil
+	}
+	dir := cfg.tlsCertDir()
+	if path.Clean(cfg.TlsCert) != path.Join(dir, nodeCertFilename) || path.Clean(cfg.TlsCertKey) != path.Join(dir, nodeKeyFilename) {
+		return fmt.Errorf("dgraph %s reads the certificate and key from one directory as %s and %s. Rename or copy them to those names", cfg.DgraphVersion, nodeCertFilename, nodeKeyFilename)
+	}
+	if _, err := os.Stat(path.Join(dir, caCertFilename)); err != nil {
+		return fmt.Errorf("dgraph %s also needs the CA certificate in %s: %v", cfg.DgraphVersion, path.Join(dir, caCertFilename), err)
+	}
+	return nil
+}
+
+// tlsCertDir is the tls_dir for releases that expect ca.crt, node.crt and
+// node.key in one directory.
+func (cfg *allConfig) tlsCertDir() string {
+	return path.Dir(cfg.TlsCert)
+}
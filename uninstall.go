@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path"
+
+	"github.com/elbow-jason/dgraph_helper/prompt"
+)
+
+// knownUnits are all the systemd units dgraph_helper may install, timers first
+// so they cannot start a service that is being removed.
+var knownUnits = []string{
+	exportTimerFilename,
+	exportServiceName + ".service",
+	ratelServiceName + ".service",
+	"dgraph.service",
+	"dgraph-server.service",
+	"dgraph-alpha.service",
+	zeroServiceName + ".service",
+}
+
+// Uninstall stops and disables every dgraph unit dgraph_helper installed and
+// removes the unit files. Data directories and config files are left alone.
+func Uninstall(args []string) {
+	flags := flag.NewFlagSet("uninstall", flag.ExitOnError)
+	yes := flags.Bool("yes", false, "do not ask for confirmation")
+	flags.Parse(args)
+	if err := ensurePermissions(); err != nil {
+		log.Fatal(err)
+	}
+	installed := installedUnits()
+	if len(installed) == 0 {
+		fmt.Println("No dgraph services are installed.")
+		return
+	}
+	fmt.Printf("Installed units: %v\n", installed)
+	if !*yes && !prompt.InputYesOrNo("Stop and remove these services? (data is kept)", "The systemd units and the logrotate config are removed. The data directories and the yaml configs stay.", false) {
+		return
+	}
+	for _, unit := range installed {
+		// stopping a unit that is not running is not an error worth stopping for
+		runCommand("systemctl", "disable", "--now", unit)
+		filename := path.Join(systemDpath, unit)
+		if err := os.Remove(filename); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Removed %s\n", filename)
+	}
+	if err := os.Remove(logrotateFilepath); err == nil {
+		fmt.Printf("Removed %s\n", logrotateFilepath)
+	}
+	reloadDaemons()
+}
+
+func installedUnits() []string {
+	installed := []string{}
+	for _, unit := range knownUnits {
+		if _, err := os.Stat(path.Join(systemDpath, unit)); err == nil {
+			installed = append(installed, unit)
+		}
+	}
+	return installed
+}
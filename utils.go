@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 var dashAndCommaRegex = regexp.MustCompile("(,|-)")
@@ -67,3 +68,18 @@ func runCommand(cmds ...string) error {
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
+
+func containsString(items []string, item string) bool {
+	for _, it := range items {
+		if it == item {
+			return true
+		}
+	}
+	return false
+}
+
+// runCommandOutput runs a command and returns its trimmed stdout.
+func runCommandOutput(cmds ...string) (string, error) {
+	output, err := exec.Command(cmds[0], cmds[1:]...).Output()
+	return strings.TrimSpace(string(output)), err
+}
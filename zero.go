@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/elbow-jason/dgraph_helper/prompt"
+)
+
+// dgraph zero listens on these ports plus its port_offset.
+const (
+	zeroGrpcPort = 5080
+	zeroHTTPPort = 6080
+)
+
+const zeroServiceName = "dgraph-zero"
+
+const zeroYamlFilename = "zero.yaml"
+
+// hasZero returns true when the release splits dgraph into zero and a data server.
+func (schema *flagSchema) hasZero() bool {
+	return schema.command != ""
+}
+
+// changeRoles asks which of zero and the data server (alpha) this host runs.
+func (cfg *allConfig) changeRoles() {
+	alpha := cfg.schema().command
+	defaults := []string{}
+	if cfg.RunZero {
+		defaults = append(defaults, "zero")
+	}
+	if cfg.RunAlpha {
+		defaults = append(defaults, alpha)
+	}
+	roles := prompt.MultiSelectStrings("Which dgraph roles does this host run?\n<<space to select/deselect, arrows to move, enter when done>>", fieldHelp("roles"), []string{"zero", alpha}, defaults)
+	cfg.RunZero = containsString(roles, "zero")
+	cfg.RunAlpha = containsString(roles, alpha)
+	if cfg.RunZero && cfg.PeerIP == "" {
+		// the data server talks to the zero on this host
+		cfg.PeerIP = "localhost"
+		cfg.PeerPort = zeroGrpcPort + cfg.ZeroPortOffset
+	}
+}
+
+// changeZeroPeer asks for a healthy zero to join unless this is the first one.
+func (cfg *allConfig) changeZeroPeer() {
+	if prompt.InputYesOrNo("Is this the first zero in the cluster?", fieldHelp("zero first"), cfg.ZeroPeerIP == "") {
+		cfg.ZeroPeerIP = ""
+		return
+	}
+	cfg.ZeroPeerIP = prompt.InputString("The IP or hostname of a healthy zero in the cluster?", fieldHelp("zero peer"), cfg.ZeroPeerIP, hostValidator())
+	cfg.ZeroPeerPort = prompt.InputInteger("The grpc port of the same zero", fieldHelp("zero peer"), cfg.ZeroPeerPort, true, prompt.PortValidator)
+}
+
+func (cfg *allConfig) changeZeroPortOffset() {
+	cfg.ZeroPortOffset = prompt.InputInteger("Value added to zero's ports (5080 grpc, 6080 http)?", fieldHelp("zero port_offset"), cfg.ZeroPortOffset, true, prompt.NonNegativeIntValidator)
+	if cfg.PeerIP == "localhost" {
+		cfg.PeerPort = zeroGrpcPort + cfg.ZeroPortOffset
+	}
+}
+
+// ZeroMy is zero's address for other zeros and data servers.
+func (cfg *allConfig) ZeroMy() string {
+	if cfg.MyIP == "" {
+		return ""
+	}
+	return net.JoinHostPort(cfg.MyIP, strconv.Itoa(zeroGrpcPort+cfg.ZeroPortOffset))
+}
+
+func (cfg *allConfig) ZeroPeer() string {
+	if cfg.ZeroPeerIP == "" {
+		return ""
+	}
+	return net.JoinHostPort(cfg.ZeroPeerIP, strconv.Itoa(cfg.ZeroPeerPort))
+}
+
+func (cfg *allConfig) zeroToYAML() ([]byte, error) {
+	params := cfg.yamlParams(true)
+	params["bindall"] = cfg.Bindall
+	yamlBytes, err := yaml.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	header := fmt.Sprintf("# written by dgraph_helper for dgraph %s zero\n", cfg.DgraphVersion)
+	return append([]byte(header), yamlBytes...), nil
+}
+
+func (cfg *allConfig) zeroDotYamlFilepath() string {
+	return path.Join(cfg.installDir, zeroYamlFilename)
+}
+
+func (cfg *allConfig) writeZeroDotYaml() {
+	yamlBytes, err := cfg.zeroToYAML()
+	if err != nil {
+		panic(err)
+	}
+	err = ioutil.WriteFile(cfg.zeroDotYamlFilepath(), yamlBytes, os.ModePerm)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (cfg *allConfig) startZeroCommand() string {
+	return fmt.Sprintf("%s zero --config=%s", dgraphBinary, cfg.zeroDotYamlFilepath())
+}
+
+func (cfg *allConfig) zeroSystemDUnit() string {
+	return systemDUnit("Dgraph zero", cfg.startZeroCommand(), nil, cfg.logDirectives(zeroServiceName))
+}
+
+func (cfg *allConfig) writeZeroSystemDUnit() {
+	err := ioutil.WriteFile(unitFilepath(zeroServiceName), []byte(cfg.zeroSystemDUnit()), os.ModePerm)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (cfg *allConfig) zeroHealthURL() string {
+	return fmt.Sprintf("http://localhost:%d/state", zeroHTTPPort+cfg.ZeroPortOffset)
+}